@@ -7,13 +7,14 @@ terraform {
 }
 
 provider "redshift" {
-  host     = var.host
-  port     = var.port
-  username = var.username
-  password = var.password
-  dbname   = var.dbname
-  sslmode  = var.sslmode
-  timeout  = 30
+  host              = var.host
+  port              = var.port
+  username          = var.username
+  password          = var.password
+  dbname            = var.dbname
+  sslmode           = var.sslmode
+  connect_timeout   = 30
+  statement_timeout = 300
 }
 
 # resource "redshift_user" "donnie1" {
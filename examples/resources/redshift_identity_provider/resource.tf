@@ -0,0 +1,25 @@
+# client_secret is stored in the Terraform state in plain text, like every
+# other attribute. Keep the state in an encrypted backend with restricted
+# access, or use client_secret_file below.
+resource "redshift_identity_provider" "azure" {
+  name          = "azure_ad"
+  namespace     = "aad"
+  issuer        = "https://sts.windows.net/00000000-0000-0000-0000-000000000000/"
+  client_id     = "11111111-1111-1111-1111-111111111111"
+  client_secret = var.azure_client_secret
+  audiences     = ["api://11111111-1111-1111-1111-111111111111"]
+}
+
+# Only the path is stored in the state, the file is read whenever the secret
+# is sent to Redshift. Redshift never returns the secret, so neither a
+# rotated file nor a change made outside Terraform shows in the plan: change
+# client_secret_version to send the secret again.
+resource "redshift_identity_provider" "azure_from_file" {
+  name                  = "azure_ad_file"
+  namespace             = "aadfile"
+  issuer                = "https://sts.windows.net/00000000-0000-0000-0000-000000000000/"
+  client_id             = "11111111-1111-1111-1111-111111111111"
+  client_secret_file    = "/run/secrets/azure_client_secret"
+  client_secret_version = "2024-03-01"
+  audiences             = ["api://11111111-1111-1111-1111-111111111111"]
+}
@@ -4,11 +4,12 @@ package generated
 
 import (
 	"context"
-	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/planmodifiers"
+	"terraform-provider-redshift/internal/validators"
 
-	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -33,19 +34,18 @@ func GroupResourceSchema(ctx context.Context) schema.Schema {
 				MarkdownDescription: "Name of the new user group. Group names beginning with two underscores are reserved for Amazon Redshift internal use. For more information about valid names, see Names and identifiers.",
 				Validators: []validator.String{
 					stringvalidator.NoneOfCaseInsensitive(`public`),
-					stringvalidator.UTF8LengthBetween(1, 127),
-					stringvalidator.NoneOfCaseInsensitive(helpers.ReservedWords...),
-					stringvalidator.RegexMatches(helpers.IdentifierValidCharacters, helpers.IdentifierValidCharactersMessage),
+					validators.IdentifierValidator(validators.IdentifierGroup),
 				},
 			},
 			"usernames": schema.SetAttribute{
 				ElementType:         types.StringType,
 				Optional:            true,
 				Computed:            true,
-				Description:         "Name(s) of the user to add to the group.",
-				MarkdownDescription: "Name(s) of the user to add to the group.",
-				Validators: []validator.Set{
-					setvalidator.IsRequired(),
+				Description:         "Name(s) of the user to add to the group. When set, membership is authoritative and users added by any other means are removed. Omit it to leave membership to redshift_group_membership resources.",
+				MarkdownDescription: "Name(s) of the user to add to the group. When set, membership is authoritative and users added by any other means are removed. Omit it to leave membership to redshift_group_membership resources.",
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+					planmodifiers.CaseInsensitiveSet(),
 				},
 			},
 		},
@@ -4,10 +4,13 @@ package generated
 
 import (
 	"context"
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-redshift/internal/validators"
 
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 )
@@ -20,33 +23,130 @@ func RedshiftProviderSchema(ctx context.Context) schema.Schema {
 				Description:         "The name of the application.  The default value is terraform-provider-redshift",
 				MarkdownDescription: "The name of the application.  The default value is terraform-provider-redshift",
 			},
+			"connect_timeout": schema.Int64Attribute{
+				Optional:            true,
+				Description:         "Maximum time in seconds to wait while connecting. Zero or unspecified means wait indefinitely.",
+				MarkdownDescription: "Maximum time in seconds to wait while connecting. Zero or unspecified means wait indefinitely.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					int64validator.ConflictsWith(path.MatchRoot("timeout")),
+				},
+			},
 			"dbname": schema.StringAttribute{
-				Required:            true,
-				Description:         "dbname",
-				MarkdownDescription: "dbname",
+				Optional:            true,
+				Description:         "dbname. Required unless dsn is set.",
+				MarkdownDescription: "dbname. Required unless dsn is set.",
 				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
 				},
 			},
+			"dsn": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				Description:         "A libpq connection string, as key/value pairs or a postgres:// URL, instead of host, port, username, password, dbname and sslmode. The other attributes still apply on top of it. As with libpq, what it leaves out comes from the PG* environment variables, which are ignored without a dsn.",
+				MarkdownDescription: "A libpq connection string, as key/value pairs or a postgres:// URL, instead of host, port, username, password, dbname and sslmode. The other attributes still apply on top of it. As with libpq, what it leaves out comes from the PG* environment variables, which are ignored without a dsn.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("dbname"), path.MatchRoot("host"), path.MatchRoot("password"), path.MatchRoot("port"), path.MatchRoot("serverless"), path.MatchRoot("sslmode"), path.MatchRoot("username")),
+				},
+			},
 			"host": schema.StringAttribute{
-				Required:            true,
-				Description:         "host",
-				MarkdownDescription: "host",
+				Optional:            true,
+				Description:         "host. With serverless, defaults to the endpoint of the workgroup.",
+				MarkdownDescription: "host. With serverless, defaults to the endpoint of the workgroup.",
+			},
+			"identifier_case": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Whether the name of every user, group and role the provider manages must be in lower case, lower, or may have any case, preserve. The default is preserve. Checked when planning.",
+				MarkdownDescription: "Whether the name of every user, group and role the provider manages must be in lower case, lower, or may have any case, preserve. The default is preserve. Checked when planning.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(validators.IdentifierCaseLower, validators.IdentifierCasePreserve),
+				},
+			},
+			"log_sql": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Which SQL is written to the provider log. off logs none, statements logs each statement once it has run, trace logs everything the driver reports including the arguments. Passwords, external ids and secret ARNs are masked. Defaults to statements.",
+				MarkdownDescription: "Which SQL is written to the provider log. off logs none, statements logs each statement once it has run, trace logs everything the driver reports including the arguments. Passwords, external ids and secret ARNs are masked. Defaults to statements.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("off", "statements", "trace"),
+				},
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:            true,
+				Description:         "How often a statement which failed with a transient error, such as a serializable isolation violation or a connection reset, is retried. Defaults to 3, zero disables retries.",
+				MarkdownDescription: "How often a statement which failed with a transient error, such as a serializable isolation violation or a connection reset, is retried. Defaults to 3, zero disables retries.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"name_prefix_required": schema.StringAttribute{
+				Optional:            true,
+				Description:         "The prefix the name of every user, group and role the provider manages must start with, checked when planning.",
+				MarkdownDescription: "The prefix the name of every user, group and role the provider manages must start with, checked when planning.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"password": schema.StringAttribute{
-				Required:            true,
+				Optional:            true,
 				Sensitive:           true,
-				Description:         "password",
-				MarkdownDescription: "password",
+				Description:         "password. With serverless, omit it to connect with temporary IAM credentials for the workgroup.",
+				MarkdownDescription: "password. With serverless, omit it to connect with temporary IAM credentials for the workgroup.",
 			},
 			"port": schema.Int64Attribute{
-				Required:            true,
-				Description:         "port",
-				MarkdownDescription: "port",
+				Optional:            true,
+				Description:         "port. With serverless, defaults to the port of the workgroup.",
+				MarkdownDescription: "port. With serverless, defaults to the port of the workgroup.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"read_only": schema.BoolAttribute{
+				Optional:            true,
+				Description:         "Refuse every statement which is not a read, such as SELECT or SHOW, and open each session with SET default_transaction_read_only. Plans and refreshes work, applying a change fails before any DDL reaches the cluster.",
+				MarkdownDescription: "Refuse every statement which is not a read, such as SELECT or SHOW, and open each session with SET default_transaction_read_only. Plans and refreshes work, applying a change fails before any DDL reaches the cluster.",
+			},
+			"retry_backoff": schema.Int64Attribute{
+				Optional:            true,
+				Description:         "Seconds to wait before the first retry, doubled for each retry after it. Defaults to 1.",
+				MarkdownDescription: "Seconds to wait before the first retry, doubled for each retry after it. Defaults to 1.",
 				Validators: []validator.Int64{
 					int64validator.AtLeast(0),
 				},
 			},
+			"sql_output_file": schema.StringAttribute{
+				Optional:            true,
+				Description:         "A file the statements which change redshift are appended to, with passwords and other secrets redacted, once the transaction running them commits.",
+				MarkdownDescription: "A file the statements which change redshift are appended to, with passwords and other secrets redacted, once the transaction running them commits.",
+			},
+			"sql_output_only": schema.BoolAttribute{
+				Optional:            true,
+				Description:         "Write the statements to sql_output_file instead of running them. Each create, update and delete then fails, leaving redshift and the state as they were.",
+				MarkdownDescription: "Write the statements to sql_output_file instead of running them. Each create, update and delete then fails, leaving redshift and the state as they were.",
+				Validators: []validator.Bool{
+					boolvalidator.AlsoRequires(path.MatchRoot("sql_output_file")),
+				},
+			},
+			"ssl_cert": schema.StringAttribute{
+				Optional:            true,
+				Description:         "The client certificate presented to the server, as a file path or inline PEM. Requires ssl_key.",
+				MarkdownDescription: "The client certificate presented to the server, as a file path or inline PEM. Requires ssl_key.",
+			},
+			"ssl_key": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				Description:         "The unencrypted private key of ssl_cert, as a file path or inline PEM.",
+				MarkdownDescription: "The unencrypted private key of ssl_cert, as a file path or inline PEM.",
+			},
+			"ssl_root_cert": schema.StringAttribute{
+				Optional:            true,
+				Description:         "The CA certificates the server certificate is verified against, as a file path or inline PEM. Without it, verify-ca and verify-full use the system certificate pool. With require, also in a dsn, it is verified as with verify-ca, as libpq does.",
+				MarkdownDescription: "The CA certificates the server certificate is verified against, as a file path or inline PEM. Without it, verify-ca and verify-full use the system certificate pool. With require, also in a dsn, it is verified as with verify-ca, as libpq does.",
+			},
+			"ssl_server_name": schema.StringAttribute{
+				Optional:            true,
+				Description:         "The server name sent with TLS and, with verify-full, checked against the server certificate instead of host. Useful when connecting through a tunnel or by IP address.",
+				MarkdownDescription: "The server name sent with TLS and, with verify-full, checked against the server certificate instead of host. Useful when connecting through a tunnel or by IP address.",
+			},
 			"sslmode": schema.StringAttribute{
 				Optional:            true,
 				Description:         "For allowed values and their descriptions, see https://www.postgresql.org/docs/11/libpq-ssl.html#LIBPQ-SSL-PROTECTION",
@@ -55,30 +155,175 @@ func RedshiftProviderSchema(ctx context.Context) schema.Schema {
 					stringvalidator.OneOf("disable", "allow", "prefer", "require", "verify-ca", "verify-full"),
 				},
 			},
+			"statement_timeout": schema.Int64Attribute{
+				Optional:            true,
+				Description:         "Maximum time in seconds a statement may run, set on each session with SET statement_timeout. Zero or unspecified leaves the default of the cluster.",
+				MarkdownDescription: "Maximum time in seconds a statement may run, set on each session with SET statement_timeout. Zero or unspecified leaves the default of the cluster.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
 			"timeout": schema.Int64Attribute{
 				Optional:            true,
-				Description:         "Timeout in seconds. Maximum time to wait while connecting and query execution. Zero or unspecified means wait indefinitely.",
-				MarkdownDescription: "Timeout in seconds. Maximum time to wait while connecting and query execution. Zero or unspecified means wait indefinitely.",
+				Description:         "Deprecated alias of connect_timeout.",
+				MarkdownDescription: "Deprecated alias of connect_timeout.",
+				DeprecationMessage:  "Use connect_timeout instead. Operations are bounded by the timeouts block of each resource, and statements by statement_timeout.",
 				Validators: []validator.Int64{
 					int64validator.AtLeast(0),
 				},
 			},
 			"username": schema.StringAttribute{
-				Required:            true,
-				Description:         "username",
-				MarkdownDescription: "username",
+				Optional:            true,
+				Description:         "username. With serverless and no password, the user comes from the temporary IAM credentials instead.",
+				MarkdownDescription: "username. With serverless and no password, the user comes from the temporary IAM credentials instead.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"audit": schema.SingleNestedBlock{
+				Attributes: map[string]schema.Attribute{
+					"schema": schema.StringAttribute{
+						Optional:            true,
+						Description:         "The schema of the table, which must exist. The default is public.",
+						MarkdownDescription: "The schema of the table, which must exist. The default is public.",
+						Validators: []validator.String{
+							validators.IdentifierValidator(validators.IdentifierStandard),
+						},
+					},
+					"table": schema.StringAttribute{
+						Required:            true,
+						Description:         "The name of the table.",
+						MarkdownDescription: "The name of the table.",
+						Validators: []validator.String{
+							validators.IdentifierValidator(validators.IdentifierStandard),
+						},
+					},
+				},
+				Description:         "Record every statement which changes redshift in a table, in the transaction running it: when, the terraform operation and resource type, the name of the object changed, the redacted statement and the user connected. The table is created when missing, in the provider's database when it is configured and in any other database before the provider first uses it.",
+				MarkdownDescription: "Record every statement which changes redshift in a table, in the transaction running it: when, the terraform operation and resource type, the name of the object changed, the redacted statement and the user connected. The table is created when missing, in the provider's database when it is configured and in any other database before the provider first uses it.",
+			},
+			"serverless": schema.SingleNestedBlock{
+				Attributes: map[string]schema.Attribute{
+					"endpoint": schema.StringAttribute{
+						Optional:            true,
+						Description:         "Overrides the URL of the redshift-serverless API, for example a VPC interface endpoint.",
+						MarkdownDescription: "Overrides the URL of the redshift-serverless API, for example a VPC interface endpoint.",
+					},
+					"region": schema.StringAttribute{
+						Required:            true,
+						Description:         "The AWS region of the workgroup.",
+						MarkdownDescription: "The AWS region of the workgroup.",
+					},
+					"workgroup_name": schema.StringAttribute{
+						Required:            true,
+						Description:         "The name of the workgroup.",
+						MarkdownDescription: "The name of the workgroup.",
+					},
+				},
+				Description:         "Connect to a Redshift Serverless workgroup. Its endpoint and, without a password, temporary credentials are looked up with the AWS credentials of the environment.",
+				MarkdownDescription: "Connect to a Redshift Serverless workgroup. Its endpoint and, without a password, temporary credentials are looked up with the AWS credentials of the environment.",
+			},
+			"ssh_tunnel": schema.SingleNestedBlock{
+				Attributes: map[string]schema.Attribute{
+					"host": schema.StringAttribute{
+						Required:            true,
+						Description:         "The host of the bastion.",
+						MarkdownDescription: "The host of the bastion.",
+					},
+					"host_key": schema.StringAttribute{
+						Optional:            true,
+						Description:         "The public key of the bastion in authorized_keys format, verified instead of a known_hosts file.",
+						MarkdownDescription: "The public key of the bastion in authorized_keys format, verified instead of a known_hosts file.",
+					},
+					"known_hosts_file": schema.StringAttribute{
+						Optional:            true,
+						Description:         "The known_hosts file the host key of the bastion is verified against. The default is ~/.ssh/known_hosts.",
+						MarkdownDescription: "The known_hosts file the host key of the bastion is verified against. The default is ~/.ssh/known_hosts.",
+					},
+					"port": schema.Int64Attribute{
+						Optional:            true,
+						Description:         "The SSH port of the bastion. The default is 22.",
+						MarkdownDescription: "The SSH port of the bastion. The default is 22.",
+						Validators: []validator.Int64{
+							int64validator.Between(1, 65535),
+						},
+					},
+					"private_key": schema.StringAttribute{
+						Optional:            true,
+						Sensitive:           true,
+						Description:         "The PEM encoded private key to authenticate with.",
+						MarkdownDescription: "The PEM encoded private key to authenticate with.",
+					},
+					"private_key_passphrase": schema.StringAttribute{
+						Optional:            true,
+						Sensitive:           true,
+						Description:         "The passphrase of an encrypted private_key.",
+						MarkdownDescription: "The passphrase of an encrypted private_key.",
+					},
+					"use_agent": schema.BoolAttribute{
+						Optional:            true,
+						Description:         "Authenticate with the keys of the SSH agent at SSH_AUTH_SOCK.",
+						MarkdownDescription: "Authenticate with the keys of the SSH agent at SSH_AUTH_SOCK.",
+					},
+					"user": schema.StringAttribute{
+						Required:            true,
+						Description:         "The user to log in to the bastion as.",
+						MarkdownDescription: "The user to log in to the bastion as.",
+					},
+				},
+				Description:         "Connect through an SSH bastion, which forwards each connection to the cluster. The cluster host is resolved by the bastion.",
+				MarkdownDescription: "Connect through an SSH bastion, which forwards each connection to the cluster. The cluster host is resolved by the bastion.",
 			},
 		},
 	}
 }
 
 type RedshiftModel struct {
-	ApplicationName types.String `tfsdk:"application_name"`
-	Dbname          types.String `tfsdk:"dbname"`
-	Host            types.String `tfsdk:"host"`
-	Password        types.String `tfsdk:"password"`
-	Port            types.Int64  `tfsdk:"port"`
-	Sslmode         types.String `tfsdk:"sslmode"`
-	Timeout         types.Int64  `tfsdk:"timeout"`
-	Username        types.String `tfsdk:"username"`
+	ApplicationName    types.String     `tfsdk:"application_name"`
+	Audit              *AuditModel      `tfsdk:"audit"`
+	ConnectTimeout     types.Int64      `tfsdk:"connect_timeout"`
+	Dbname             types.String     `tfsdk:"dbname"`
+	Dsn                types.String     `tfsdk:"dsn"`
+	Host               types.String     `tfsdk:"host"`
+	IdentifierCase     types.String     `tfsdk:"identifier_case"`
+	LogSql             types.String     `tfsdk:"log_sql"`
+	MaxRetries         types.Int64      `tfsdk:"max_retries"`
+	NamePrefixRequired types.String     `tfsdk:"name_prefix_required"`
+	Password           types.String     `tfsdk:"password"`
+	Port               types.Int64      `tfsdk:"port"`
+	ReadOnly           types.Bool       `tfsdk:"read_only"`
+	RetryBackoff       types.Int64      `tfsdk:"retry_backoff"`
+	Serverless         *ServerlessModel `tfsdk:"serverless"`
+	SqlOutputFile      types.String     `tfsdk:"sql_output_file"`
+	SqlOutputOnly      types.Bool       `tfsdk:"sql_output_only"`
+	SshTunnel          *SshTunnelModel  `tfsdk:"ssh_tunnel"`
+	SslCert            types.String     `tfsdk:"ssl_cert"`
+	SslKey             types.String     `tfsdk:"ssl_key"`
+	SslRootCert        types.String     `tfsdk:"ssl_root_cert"`
+	SslServerName      types.String     `tfsdk:"ssl_server_name"`
+	Sslmode            types.String     `tfsdk:"sslmode"`
+	StatementTimeout   types.Int64      `tfsdk:"statement_timeout"`
+	Timeout            types.Int64      `tfsdk:"timeout"`
+	Username           types.String     `tfsdk:"username"`
+}
+
+type AuditModel struct {
+	Schema types.String `tfsdk:"schema"`
+	Table  types.String `tfsdk:"table"`
+}
+
+type ServerlessModel struct {
+	Endpoint      types.String `tfsdk:"endpoint"`
+	Region        types.String `tfsdk:"region"`
+	WorkgroupName types.String `tfsdk:"workgroup_name"`
+}
+
+type SshTunnelModel struct {
+	Host                 types.String `tfsdk:"host"`
+	HostKey              types.String `tfsdk:"host_key"`
+	KnownHostsFile       types.String `tfsdk:"known_hosts_file"`
+	Port                 types.Int64  `tfsdk:"port"`
+	PrivateKey           types.String `tfsdk:"private_key"`
+	PrivateKeyPassphrase types.String `tfsdk:"private_key_passphrase"`
+	UseAgent             types.Bool   `tfsdk:"use_agent"`
+	User                 types.String `tfsdk:"user"`
 }
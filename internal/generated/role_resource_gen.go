@@ -9,7 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/validators"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 )
@@ -39,8 +39,7 @@ func RoleResourceSchema(ctx context.Context) schema.Schema {
 				MarkdownDescription: "The name of the role. The role name must be unique and can't be the same as any user names. A role name can't be a reserved word.",
 				Validators: []validator.String{
 					stringvalidator.NoneOfCaseInsensitive(`public`),
-					stringvalidator.UTF8LengthBetween(1, 127),
-					stringvalidator.NoneOfCaseInsensitive(helpers.ReservedWords...),
+					validators.IdentifierValidator(validators.IdentifierQuoted),
 				},
 			},
 		},
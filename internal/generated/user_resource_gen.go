@@ -69,8 +69,7 @@ func UserResourceSchema(ctx context.Context) schema.Schema {
 				MarkdownDescription: "The name of the user to create. The user name can't be PUBLIC. For more information about valid names, see Names and identifiers.",
 				Validators: []validator.String{
 					stringvalidator.NoneOfCaseInsensitive(`public`),
-					stringvalidator.UTF8LengthBetween(1, 127),
-					stringvalidator.NoneOfCaseInsensitive(helpers.ReservedWords...),
+					validators.IdentifierValidator(validators.IdentifierQuoted),
 					stringvalidator.NoneOfCaseInsensitive(helpers.SystemColumnNames...),
 				},
 			},
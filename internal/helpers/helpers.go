@@ -63,3 +63,193 @@ func SetValueOrNull[T any](ctx context.Context, elementType attr.Type, elements
 	diags.Append(d...)
 	return result
 }
+
+// IdSeparator joins the parts of a composite resource identifier.
+const IdSeparator = "|"
+
+// Join the parts into a composite resource identifier.
+func JoinId(parts ...string) string {
+	return strings.Join(parts, IdSeparator)
+}
+
+// Split a composite resource identifier into exactly n parts.
+func SplitId(id string, n int) ([]string, error) {
+	parts := strings.Split(id, IdSeparator)
+	if len(parts) != n {
+		return nil, fmt.Errorf("SplitId: expected %d parts separated by '%s' in identifier '%s', got %d", n, IdSeparator, id, len(parts))
+	}
+
+	return parts, nil
+}
+
+// Escape a value for use inside a single quoted string literal.
+func EscapeLiteral(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
+}
+
+// Returns the name as redshift stores it, both unquoted and quoted identifiers
+// are folded to lower case unless enable_case_sensitive_identifier is on.
+func NormalizeIdentifier(name string, caseSensitive bool) string {
+	if caseSensitive {
+		return name
+	}
+
+	return strings.ToLower(name)
+}
+
+// returns the actual names, spelled as in prior wherever the two only differ
+// by case, so a configured spelling survives reading back the catalog. A name
+// in prior as it is in actual is kept, for clusters where case tells names
+// apart.
+func ReconcileIdentifiers(prior []string, actual []string) []string {
+	result := []string{}
+
+	for _, a := range actual {
+		name := a
+		if !slices.Contains(prior, a) {
+			for _, p := range prior {
+				if strings.EqualFold(p, a) {
+					name = p
+					break
+				}
+			}
+		}
+		result = append(result, name)
+	}
+
+	return result
+}
+
+// Returns the expression as redshift prints it back, for comparing a
+// configured predicate with the catalog. Outside of quotes, letters are
+// folded to lower case and whitespace is only kept between words; parentheses
+// around the whole expression are dropped.
+func NormalizeExpression(expr string) string {
+	var b strings.Builder
+
+	space, punctuation := false, true
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+
+		switch {
+		case c == '\'' || c == '"':
+			end := closingQuote(expr, i)
+			if space && !punctuation {
+				b.WriteByte(' ')
+			}
+			b.WriteString(expr[i:end])
+			i = end - 1
+			space, punctuation = false, false
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+		default:
+			isPunctuation := strings.IndexByte("()[],;=<>!+-*/%|&:^~", c) >= 0
+			if space && !punctuation && !isPunctuation {
+				b.WriteByte(' ')
+			}
+			if 'A' <= c && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+			b.WriteByte(c)
+			space, punctuation = false, isPunctuation
+		}
+	}
+
+	normalized := b.String()
+	for strings.HasPrefix(normalized, "(") && closingParenthesis(normalized, 0) == len(normalized)-1 {
+		normalized = normalized[1 : len(normalized)-1]
+	}
+
+	return normalized
+}
+
+// Returns the index after the quote closing the one at start, a doubled
+// quote is part of the quoted text.
+func closingQuote(s string, start int) int {
+	quote := s[start]
+	for i := start + 1; i < len(s); i++ {
+		if s[i] != quote {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+
+	return len(s)
+}
+
+// Returns the index of the parenthesis closing the one at start, or -1.
+func closingParenthesis(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\'', '"':
+			i = closingQuote(s, i) - 1
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// The names redshift gives the data types it accepts under other names, and
+// the length or precision it assumes when none is given.
+var typeNames = map[string]string{
+	"int":         "integer",
+	"int4":        "integer",
+	"int2":        "smallint",
+	"int8":        "bigint",
+	"bool":        "boolean",
+	"float":       "double precision",
+	"float8":      "double precision",
+	"float4":      "real",
+	"decimal":     "numeric",
+	"varchar":     "character varying",
+	"nvarchar":    "character varying",
+	"text":        "character varying",
+	"char":        "character",
+	"nchar":       "character",
+	"bpchar":      "character",
+	"timestamp":   "timestamp without time zone",
+	"timestamptz": "timestamp with time zone",
+	"time":        "time without time zone",
+	"timetz":      "time with time zone",
+}
+
+var typeDefaults = map[string]string{
+	"character varying": "(256)",
+	"character":         "(1)",
+	"numeric":           "(18,0)",
+}
+
+// Returns the data type as redshift names it, for comparing a configured
+// type with the catalog.
+func NormalizeType(typ string) string {
+	typ = strings.ToLower(strings.Join(strings.Fields(typ), " "))
+
+	name, modifier := typ, ""
+	if i := strings.IndexByte(typ, '('); i >= 0 {
+		name, modifier = strings.TrimSpace(typ[:i]), strings.ReplaceAll(typ[i:], " ", "")
+	}
+
+	if canonical, ok := typeNames[name]; ok {
+		name = canonical
+	}
+	switch {
+	case modifier == "":
+		modifier = typeDefaults[name]
+	case modifier == "(max)" && name == "character varying":
+		modifier = "(65535)"
+	}
+
+	return name + modifier
+}
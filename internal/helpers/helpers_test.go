@@ -62,3 +62,78 @@ func Test_MissingFrom(t *testing.T) {
 	assert.Equal(t, []string{"hello"}, MissingFrom(slice1, slice2))
 	assert.Equal(t, []string{"world"}, MissingFrom(slice2, slice1))
 }
+
+func Test_JoinId_SplitId(t *testing.T) {
+	id := JoinId("policy", "public", "table")
+	assert.Equal(t, "policy|public|table", id)
+
+	parts, err := SplitId(id, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"policy", "public", "table"}, parts)
+
+	_, err = SplitId(id, 2)
+	assert.NotNil(t, err)
+}
+
+func Test_EscapeLiteral(t *testing.T) {
+	assert.Equal(t, "plain", EscapeLiteral("plain"))
+	assert.Equal(t, "it''s", EscapeLiteral("it's"))
+	assert.Equal(t, "''''", EscapeLiteral("''"))
+}
+
+func Test_NormalizeIdentifier(t *testing.T) {
+	assert.Equal(t, "mixedcase", NormalizeIdentifier("MixedCase", false))
+	assert.Equal(t, "MixedCase", NormalizeIdentifier("MixedCase", true))
+}
+
+func Test_ReconcileIdentifiers(t *testing.T) {
+	prior := []string{"Alice", "bob", "Carol"}
+	actual := []string{"alice", "bob", "dave"}
+
+	assert.Equal(t, []string{"Alice", "bob", "dave"}, ReconcileIdentifiers(prior, actual))
+	assert.Equal(t, []string{}, ReconcileIdentifiers(prior, nil))
+
+	// names told apart by case keep their own spelling
+	assert.Equal(t, []string{"Email", "email"}, ReconcileIdentifiers([]string{"Email", "email"}, []string{"Email", "email"}))
+}
+
+func Test_NormalizeExpression(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		configured string
+		catalog    string
+		equal      bool
+	}{
+		"spacing_and_case":  {"Owner_Id = CURRENT_USER_ID", "(owner_id=current_user_id)", true},
+		"line_breaks":       {"a = 1\n  AND b = 2", "(a=1 and b=2)", true},
+		"words_kept_apart":  {"a IS NOT NULL", "a is not null", true},
+		"quoted_text":       {"name = 'Alice'", "(name='alice')", false},
+		"quoted_spaces":     {"name = 'a  b'", "(name='a  b')", true},
+		"escaped_quote":     {"name = 'it''s (x'", "name='it''s (x'", true},
+		"inner_parentheses": {"(a = 1) OR (b = 2)", "(a=1) or (b=2)", true},
+		"other_value":       {"owner_id = 1", "(owner_id=2)", false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.equal, NormalizeExpression(test.configured) == NormalizeExpression(test.catalog))
+		})
+	}
+}
+
+func Test_NormalizeType(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "integer", NormalizeType("INT"))
+	assert.Equal(t, "character varying(256)", NormalizeType("varchar"))
+	assert.Equal(t, "character varying(256)", NormalizeType("TEXT"))
+	assert.Equal(t, "character varying(65535)", NormalizeType("VARCHAR(MAX)"))
+	assert.Equal(t, "character varying(10)", NormalizeType("character  varying ( 10 )"))
+	assert.Equal(t, "numeric(10,2)", NormalizeType("decimal(10, 2)"))
+	assert.Equal(t, "numeric(18,0)", NormalizeType("numeric"))
+	assert.Equal(t, "timestamp without time zone", NormalizeType("timestamp"))
+}
@@ -1,10 +1,5 @@
 package helpers
 
-import (
-	"fmt"
-	"regexp"
-)
-
 /*
 Names identify database objects, including tables and columns, as well as users and passwords.
 The terms name and identifier can be used interchangeably. There are two types of identifiers,
@@ -28,11 +23,8 @@ var SystemColumnNames = []string{
 	"ctid",
 }
 
-// Begin with an ASCII single-byte alphabetic character or underscore character.
-// Subsequent characters can be ASCII single-byte alphanumeric characters, underscores, or dollar signs.
-var IdentifierValidCharactersPattern = `^[a-zA-Z_][a-zA-Z0-9_\$]*$`
-var IdentifierValidCharacters = regexp.MustCompile(IdentifierValidCharactersPattern)
-var IdentifierValidCharactersMessage = fmt.Sprintf("Must match identifier regex pattern %s", IdentifierValidCharactersPattern)
+// The longest name redshift accepts, in bytes of UTF-8 rather than characters.
+const IdentifierMaxBytes = 127
 
 // The following is a list of Amazon Redshift reserved words. You can use the reserved words with delimited
 // identifiers (double quotation marks).  See https://docs.aws.amazon.com/redshift/latest/dg/r_pg_keywords.html
@@ -166,7 +158,7 @@ var ReservedWords = []string{
 	"select",
 	"session_user",
 	"similar",
-	"snapshot ",
+	"snapshot",
 	"some",
 	"sysdate",
 	"system",
@@ -0,0 +1,205 @@
+package localredshift
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgproto3"
+)
+
+// proxy relays the postgres wire protocol between the provider and the
+// upstream server, translating each statement on the way.
+type proxy struct {
+	listener net.Listener
+	upstream string
+	wg       sync.WaitGroup
+}
+
+func newProxy(upstream string) (*proxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("newProxy: Failed to listen: %w", err)
+	}
+
+	p := &proxy{listener: listener, upstream: upstream}
+
+	p.wg.Add(1)
+	go p.serve()
+
+	return p, nil
+}
+
+func (p *proxy) port() int {
+	return p.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (p *proxy) close() {
+	p.listener.Close()
+	p.wg.Wait()
+}
+
+func (p *proxy) serve() {
+	defer p.wg.Done()
+
+	for {
+		client, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go p.handle(client)
+	}
+}
+
+func (p *proxy) handle(client net.Conn) {
+	defer client.Close()
+
+	backend := pgproto3.NewBackend(client, client)
+
+	startup, err := receiveStartup(client, backend)
+	if err != nil || startup == nil {
+		return
+	}
+
+	server, err := net.Dial("tcp", p.upstream)
+	if err != nil {
+		return
+	}
+	defer server.Close()
+
+	frontend := pgproto3.NewFrontend(server, server)
+	frontend.Send(startup)
+	if err := frontend.Flush(); err != nil {
+		return
+	}
+
+	if !authenticate(backend, frontend) {
+		return
+	}
+
+	// relay the server's responses while the client's requests are read
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer client.Close()
+
+		for {
+			msg, err := frontend.Receive()
+			if err != nil {
+				return
+			}
+
+			backend.Send(msg)
+			if err := backend.Flush(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			break
+		}
+
+		switch msg := msg.(type) {
+		case *pgproto3.Query:
+			msg.String = Translate(msg.String)
+		case *pgproto3.Parse:
+			msg.Query = Translate(msg.Query)
+		}
+
+		frontend.Send(msg)
+		if err := frontend.Flush(); err != nil {
+			break
+		}
+
+		if _, ok := msg.(*pgproto3.Terminate); ok {
+			break
+		}
+	}
+
+	server.Close()
+	<-done
+}
+
+// declines SSL and GSS encryption, the proxy only listens on localhost.
+func receiveStartup(client net.Conn, backend *pgproto3.Backend) (*pgproto3.StartupMessage, error) {
+	for {
+		msg, err := backend.ReceiveStartupMessage()
+		if err != nil {
+			return nil, err
+		}
+
+		switch msg := msg.(type) {
+		case *pgproto3.SSLRequest, *pgproto3.GSSEncRequest:
+			_, err := client.Write([]byte("N"))
+			if err != nil {
+				return nil, err
+			}
+		case *pgproto3.StartupMessage:
+			return msg, nil
+		default:
+			// cancel requests are not supported
+			return nil, nil
+		}
+	}
+}
+
+// relays the authentication exchange until the server is ready for queries.
+// The backend has to be told which response to expect to each request.
+func authenticate(backend *pgproto3.Backend, frontend *pgproto3.Frontend) bool {
+	for {
+		msg, err := frontend.Receive()
+		if err != nil {
+			return false
+		}
+
+		var authType uint32
+		expectResponse := true
+
+		switch msg.(type) {
+		case *pgproto3.AuthenticationCleartextPassword:
+			authType = pgproto3.AuthTypeCleartextPassword
+		case *pgproto3.AuthenticationMD5Password:
+			authType = pgproto3.AuthTypeMD5Password
+		case *pgproto3.AuthenticationSASL:
+			authType = pgproto3.AuthTypeSASL
+		case *pgproto3.AuthenticationSASLContinue:
+			authType = pgproto3.AuthTypeSASLContinue
+		default:
+			expectResponse = false
+		}
+
+		backend.Send(msg)
+		if err := backend.Flush(); err != nil {
+			return false
+		}
+
+		switch msg.(type) {
+		case *pgproto3.ReadyForQuery:
+			return true
+		case *pgproto3.ErrorResponse:
+			return false
+		}
+
+		if !expectResponse {
+			continue
+		}
+
+		if err := backend.SetAuthType(authType); err != nil {
+			return false
+		}
+
+		response, err := backend.Receive()
+		if err != nil {
+			return false
+		}
+
+		frontend.Send(response)
+		if err := frontend.Flush(); err != nil {
+			return false
+		}
+	}
+}
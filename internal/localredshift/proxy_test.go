@@ -0,0 +1,119 @@
+package localredshift
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingUpstream is a postgres which answers every simple query with an
+// empty command and records what it was sent.
+type recordingUpstream struct {
+	listener net.Listener
+	mu       sync.Mutex
+	queries  []string
+}
+
+func (u *recordingUpstream) serve() {
+	for {
+		conn, err := u.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go u.handle(conn)
+	}
+}
+
+func (u *recordingUpstream) handle(conn net.Conn) {
+	defer conn.Close()
+
+	backend := pgproto3.NewBackend(conn, conn)
+	if _, err := backend.ReceiveStartupMessage(); err != nil {
+		return
+	}
+
+	backend.Send(&pgproto3.AuthenticationOk{})
+	backend.Send(&pgproto3.ParameterStatus{Name: "standard_conforming_strings", Value: "on"})
+	backend.Send(&pgproto3.ParameterStatus{Name: "client_encoding", Value: "UTF8"})
+	backend.Send(&pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 1})
+	backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+	if err := backend.Flush(); err != nil {
+		return
+	}
+
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			return
+		}
+
+		query, ok := msg.(*pgproto3.Query)
+		if !ok {
+			return
+		}
+
+		u.mu.Lock()
+		u.queries = append(u.queries, query.String)
+		u.mu.Unlock()
+
+		backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("OK")})
+		backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+		if err := backend.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func Test_proxy_translates_statements(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer listener.Close()
+
+	upstream := &recordingUpstream{listener: listener}
+	go upstream.serve()
+
+	p, err := newProxy(listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to start proxy: %s", err)
+	}
+	defer p.close()
+
+	// prefer exercises the declined SSL request
+	cfg, err := pgx.ParseConfig(fmt.Sprintf("host=127.0.0.1 port=%d user=admin dbname=dev sslmode=prefer", p.port()))
+	if err != nil {
+		t.Fatalf("failed to parse config: %s", err)
+	}
+
+	ctx := context.Background()
+	conn, err := pgx.ConnectConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to connect: %s", err)
+	}
+
+	_, err = conn.Exec(ctx, `CREATE ROLE "analysts"`)
+	assert.NoError(t, err)
+
+	_, err = conn.Exec(ctx, `DROP ROLE "analysts"`)
+	assert.NoError(t, err)
+
+	conn.Close(ctx)
+
+	upstream.mu.Lock()
+	defer upstream.mu.Unlock()
+
+	assert.Equal(t, []string{
+		"CREATE ROLE \"analysts\";\nALTER ROLE \"analysts\" SET redshift.kind = 'role'",
+		`DROP ROLE "analysts"`,
+	}, upstream.queries)
+}
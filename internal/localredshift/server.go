@@ -0,0 +1,241 @@
+// Package localredshift runs a local PostgreSQL which stands in for a
+// redshift cluster in acceptance tests. The catalog views the provider reads
+// are emulated by shim.sql and the redshift only syntax it sends is rewritten
+// by a proxy in front of the server, see Translate.
+package localredshift
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// names a directory holding initdb and postgres, PATH is searched otherwise
+	EnvPostgresBin = "REDSHIFT_LOCAL_PG_BIN"
+	// a running postgres to use instead of starting one, as a connection string
+	EnvPostgresDSN = "REDSHIFT_LOCAL_PG_DSN"
+
+	defaultUsername = "admin"
+	defaultDbname   = "dev"
+)
+
+// ErrNoPostgres is returned by Start when neither a postgres binary nor a
+// running server was found.
+var ErrNoPostgres = errors.New("no postgres found, install it or set " + EnvPostgresBin + " or " + EnvPostgresDSN)
+
+//go:embed shim.sql
+var shim string
+
+type Server struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Dbname   string
+
+	upstream *pgx.ConnConfig
+	proxy    *proxy
+	cmd      *exec.Cmd
+	dataDir  string
+}
+
+// Start runs postgres, or connects to the one named by REDSHIFT_LOCAL_PG_DSN,
+// installs the shim and listens on a proxy which the provider connects to.
+func Start(ctx context.Context) (*Server, error) {
+	s := &Server{}
+
+	var err error
+	if dsn, ok := os.LookupEnv(EnvPostgresDSN); ok {
+		s.upstream, err = pgx.ParseConfig(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("Start: Failed to parse %s: %w", EnvPostgresDSN, err)
+		}
+	} else {
+		err = s.startPostgres(ctx)
+		if err != nil {
+			s.Stop()
+			return nil, err
+		}
+	}
+
+	err = s.installShim(ctx)
+	if err != nil {
+		s.Stop()
+		return nil, err
+	}
+
+	s.proxy, err = newProxy(net.JoinHostPort(s.upstream.Host, strconv.Itoa(int(s.upstream.Port))))
+	if err != nil {
+		s.Stop()
+		return nil, err
+	}
+
+	s.Host = "127.0.0.1"
+	s.Port = s.proxy.port()
+	s.Username = s.upstream.User
+	s.Password = s.upstream.Password
+	s.Dbname = s.upstream.Database
+
+	return s, nil
+}
+
+// TerraformVariables are the values for the acceptance tests' provider
+// config, keyed by variable name.
+func (s *Server) TerraformVariables() map[string]string {
+	password := s.Password
+	if password == "" {
+		// the provider requires one, trust authentication ignores it
+		password = "unused"
+	}
+
+	return map[string]string{
+		"host":     s.Host,
+		"port":     strconv.Itoa(s.Port),
+		"username": s.Username,
+		"password": password,
+		"dbname":   s.Dbname,
+		"sslmode":  "disable",
+	}
+}
+
+// Stop shuts the proxy and any postgres started by Start down.
+func (s *Server) Stop() error {
+	if s.proxy != nil {
+		s.proxy.close()
+	}
+
+	var err error
+	if s.cmd != nil && s.cmd.Process != nil {
+		// SIGINT is postgres' fast shutdown
+		_ = s.cmd.Process.Signal(os.Interrupt)
+		err = s.cmd.Wait()
+	}
+
+	if s.dataDir != "" {
+		os.RemoveAll(s.dataDir)
+	}
+
+	return err
+}
+
+func (s *Server) startPostgres(ctx context.Context) error {
+	initdb, err := lookPostgresBin("initdb")
+	if err != nil {
+		return err
+	}
+
+	postgres, err := lookPostgresBin("postgres")
+	if err != nil {
+		return err
+	}
+
+	s.dataDir, err = os.MkdirTemp("", "localredshift")
+	if err != nil {
+		return fmt.Errorf("startPostgres: Failed to create data directory: %w", err)
+	}
+
+	out, err := exec.CommandContext(ctx, initdb, "-D", s.dataDir, "-U", defaultUsername, "--auth=trust", "-E", "UTF8", "--no-sync").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("startPostgres: initdb failed: %w\n%s", err, out)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		return err
+	}
+
+	s.cmd = exec.Command(postgres, "-D", s.dataDir, "-h", "127.0.0.1", "-p", strconv.Itoa(port), "-k", s.dataDir, "-c", "fsync=off")
+	err = s.cmd.Start()
+	if err != nil {
+		return fmt.Errorf("startPostgres: Failed to start postgres: %w", err)
+	}
+
+	s.upstream, err = pgx.ParseConfig(fmt.Sprintf("host=127.0.0.1 port=%d user=%s dbname=postgres sslmode=disable", port, defaultUsername))
+	if err != nil {
+		return fmt.Errorf("startPostgres: Failed to parse config: %w", err)
+	}
+
+	conn, err := waitForPostgres(ctx, s.upstream)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, "CREATE DATABASE "+defaultDbname)
+	if err != nil {
+		return fmt.Errorf("startPostgres: Failed to create database: %w", err)
+	}
+	s.upstream.Database = defaultDbname
+
+	return nil
+}
+
+func (s *Server) installShim(ctx context.Context) error {
+	conn, err := pgx.ConnectConfig(ctx, s.upstream)
+	if err != nil {
+		return fmt.Errorf("installShim: Unable to connect %w", err)
+	}
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, shim)
+	if err != nil {
+		return fmt.Errorf("installShim: Failed to execute: %w", err)
+	}
+
+	return nil
+}
+
+func lookPostgresBin(name string) (string, error) {
+	if dir, ok := os.LookupEnv(EnvPostgresBin); ok {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("lookPostgresBin: %w", err)
+		}
+		return path, nil
+	}
+
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", ErrNoPostgres
+	}
+
+	return path, nil
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("freePort: %w", err)
+	}
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func waitForPostgres(ctx context.Context, cfg *pgx.ConnConfig) (*pgx.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	for {
+		conn, err := pgx.ConnectConfig(ctx, cfg)
+		if err == nil {
+			return conn, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waitForPostgres: postgres did not start: %w", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package localredshift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Start(t *testing.T) {
+	ctx := context.Background()
+
+	server, err := Start(ctx)
+	if errors.Is(err, ErrNoPostgres) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("failed to start: %s", err)
+	}
+	defer server.Stop()
+
+	vars := server.TerraformVariables()
+	conn, err := pgx.Connect(ctx, fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		vars["host"], vars["port"], vars["username"], vars["password"], vars["dbname"], vars["sslmode"]))
+	if err != nil {
+		t.Fatalf("failed to connect: %s", err)
+	}
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, `CREATE USER "bob" PASSWORD DISABLE SYSLOG ACCESS UNRESTRICTED CONNECTION LIMIT UNLIMITED SESSION TIMEOUT 60`)
+	if err != nil {
+		t.Fatalf("failed to create user: %s", err)
+	}
+
+	var syslogAccess, connectionLimit string
+	var sessionTimeout int64
+	err = conn.QueryRow(ctx, "SELECT syslog_access, connection_limit, session_timeout FROM svv_user_info WHERE user_name = 'bob'").Scan(&syslogAccess, &connectionLimit, &sessionTimeout)
+	if err != nil {
+		t.Fatalf("failed to read svv_user_info: %s", err)
+	}
+
+	assert.Equal(t, "UNRESTRICTED", syslogAccess)
+	assert.Equal(t, "UNLIMITED", connectionLimit)
+	assert.Equal(t, int64(60), sessionTimeout)
+
+	var setting string
+	err = conn.QueryRow(ctx, "SELECT current_setting('enable_case_sensitive_identifier')").Scan(&setting)
+	if err != nil {
+		t.Fatalf("failed to read enable_case_sensitive_identifier: %s", err)
+	}
+	assert.Equal(t, "off", setting)
+}
@@ -0,0 +1,47 @@
+-- Emulates the redshift catalog views the provider reads, on postgres.
+-- Options postgres has no column for are kept as role settings, see translate.go.
+
+DO $$
+BEGIN
+	EXECUTE format('ALTER DATABASE %I SET redshift.enable_case_sensitive_identifier = ''off''', current_database());
+END
+$$;
+
+CREATE OR REPLACE FUNCTION redshift_role_setting(role oid, name text)
+RETURNS text
+LANGUAGE sql
+STABLE
+AS $$
+	SELECT substr(setting, length(name) + 2)
+	  FROM pg_catalog.pg_db_role_setting s,
+		   unnest(s.setconfig) setting
+	 WHERE s.setrole = role
+	   AND s.setdatabase = 0
+	   AND setting LIKE name || '=%'
+$$;
+
+CREATE OR REPLACE VIEW svv_user_info AS
+SELECT r.rolname::text AS user_name,
+	   r.oid::int AS user_id,
+	   r.rolcreatedb AS createdb,
+	   r.rolsuper AS superuser,
+	   CASE WHEN r.rolconnlimit < 0 THEN 'UNLIMITED' ELSE r.rolconnlimit::text END AS connection_limit,
+	   coalesce(redshift_role_setting(r.oid, 'redshift.syslog_access'), 'RESTRICTED') AS syslog_access,
+	   coalesce(redshift_role_setting(r.oid, 'redshift.session_timeout')::int, 0) AS session_timeout,
+	   redshift_role_setting(r.oid, 'redshift.external_id') AS external_user_id
+  FROM pg_catalog.pg_roles r
+ WHERE r.rolcanlogin;
+
+CREATE OR REPLACE VIEW pg_user_info AS
+SELECT u.usesysid::int AS usesysid,
+	   u.usename::text AS usename,
+	   u.valuntil
+  FROM pg_catalog.pg_user u;
+
+CREATE OR REPLACE VIEW svv_roles AS
+SELECT r.oid::int AS role_id,
+	   r.rolname::text AS role_name,
+	   (SELECT o.rolname::text FROM pg_catalog.pg_roles o WHERE o.oid = 10) AS role_owner,
+	   redshift_role_setting(r.oid, 'redshift.external_id') AS external_id
+  FROM pg_catalog.pg_roles r
+ WHERE redshift_role_setting(r.oid, 'redshift.kind') = 'role';
@@ -0,0 +1,104 @@
+package localredshift
+
+import (
+	"regexp"
+	"strings"
+)
+
+// settings the shim views read back, redshift options without a postgres
+// equivalent are kept as role settings under these names.
+const (
+	settingPrefix       = "redshift."
+	settingKind         = settingPrefix + "kind"
+	settingSyslogAccess = settingPrefix + "syslog_access"
+	settingTimeout      = settingPrefix + "session_timeout"
+	settingExternalId   = settingPrefix + "external_id"
+)
+
+var (
+	userOrRole  = regexp.MustCompile(`(?is)^\s*(CREATE|ALTER)\s+(USER|ROLE)\s+("(?:[^"]|"")*"|[^\s;]+)(.*?)\s*;?\s*$`)
+	createGroup = regexp.MustCompile(`(?is)^\s*CREATE\s+GROUP\s`)
+
+	renameTo     = regexp.MustCompile(`(?is)\bRENAME\s+TO\s+("(?:[^"]|"")*"|[^\s;]+)`)
+	syslogAccess = regexp.MustCompile(`(?is)\bSYSLOG\s+ACCESS\s+(\w+)`)
+	resetTimeout = regexp.MustCompile(`(?is)\bRESET\s+SESSION\s+TIMEOUT\b`)
+	timeout      = regexp.MustCompile(`(?is)\bSESSION\s+TIMEOUT\s+(\d+)`)
+	externalId   = regexp.MustCompile(`(?is)\bEXTERNALID\s+'((?:[^']|'')*)'`)
+
+	passwordDisable = regexp.MustCompile(`(?i)\bPASSWORD\s+DISABLE\b`)
+	noCreateUser    = regexp.MustCompile(`(?i)\bNOCREATEUSER\b`)
+	createUser      = regexp.MustCompile(`(?i)\bCREATEUSER\b`)
+	unlimited       = regexp.MustCompile(`(?i)\bCONNECTION\s+LIMIT\s+UNLIMITED\b`)
+	withUser        = regexp.MustCompile(`(?i)\bWITH\s+USER\b`)
+	caseSensitive   = regexp.MustCompile(`\benable_case_sensitive_identifier\b`)
+)
+
+// Translate rewrites a statement the provider sends to redshift into one or
+// more postgres statements. Statements postgres already understands are
+// returned unchanged.
+func Translate(sql string) string {
+	// a custom setting needs a prefix in postgres
+	sql = caseSensitive.ReplaceAllString(sql, settingPrefix+"enable_case_sensitive_identifier")
+
+	if createGroup.MatchString(sql) {
+		// postgres spells redshift's member list as ROLE
+		return withUser.ReplaceAllString(sql, "WITH ROLE")
+	}
+
+	m := userOrRole.FindStringSubmatch(sql)
+	if m == nil {
+		return sql
+	}
+
+	verb, kind, name, options := strings.ToUpper(m[1]), strings.ToUpper(m[2]), m[3], m[4]
+
+	var statements []string
+	// redshift renames a role and alters it in one statement
+	if rn := renameTo.FindStringSubmatch(options); rn != nil && kind == "ROLE" {
+		statements = append(statements, "ALTER ROLE "+name+" RENAME TO "+rn[1])
+		options = renameTo.ReplaceAllString(options, "")
+		name = rn[1]
+	}
+
+	var settings []string
+	set := func(setting, value string) {
+		settings = append(settings, "ALTER ROLE "+name+" SET "+setting+" = '"+value+"'")
+	}
+
+	if verb == "CREATE" && kind == "ROLE" {
+		set(settingKind, "role")
+	}
+
+	if sa := syslogAccess.FindStringSubmatch(options); sa != nil {
+		set(settingSyslogAccess, strings.ToUpper(sa[1]))
+		options = syslogAccess.ReplaceAllString(options, "")
+	}
+
+	if resetTimeout.MatchString(options) {
+		settings = append(settings, "ALTER ROLE "+name+" RESET "+settingTimeout)
+		options = resetTimeout.ReplaceAllString(options, "")
+	}
+
+	if st := timeout.FindStringSubmatch(options); st != nil {
+		set(settingTimeout, st[1])
+		options = timeout.ReplaceAllString(options, "")
+	}
+
+	if ei := externalId.FindStringSubmatch(options); ei != nil {
+		set(settingExternalId, ei[1])
+		options = externalId.ReplaceAllString(options, "")
+	}
+
+	options = passwordDisable.ReplaceAllString(options, "PASSWORD NULL")
+	options = noCreateUser.ReplaceAllString(options, "NOSUPERUSER")
+	options = createUser.ReplaceAllString(options, "SUPERUSER")
+	options = unlimited.ReplaceAllString(options, "CONNECTION LIMIT -1")
+
+	// an ALTER left without options is a syntax error in postgres
+	options = strings.TrimSpace(options)
+	if verb == "CREATE" || options != "" {
+		statements = append(statements, strings.TrimSpace(verb+" "+kind+" "+name+" "+options))
+	}
+
+	return strings.Join(append(statements, settings...), ";\n")
+}
@@ -0,0 +1,89 @@
+package localredshift
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Translate(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		input    string
+		expected string
+	}
+	tests := map[string]testCase{
+		"postgres_statement": {
+			input:    `DROP USER "bob"`,
+			expected: `DROP USER "bob"`,
+		},
+		"case_sensitive_identifier": {
+			input:    "SET LOCAL enable_case_sensitive_identifier TO true",
+			expected: "SET LOCAL redshift.enable_case_sensitive_identifier TO true",
+		},
+		"current_setting": {
+			input:    "SELECT current_setting('enable_case_sensitive_identifier')",
+			expected: "SELECT current_setting('redshift.enable_case_sensitive_identifier')",
+		},
+		"create_user": {
+			input: `
+				CREATE USER "bob"
+					PASSWORD DISABLE
+					NOCREATEDB
+					CREATEUSER
+					SYSLOG ACCESS unrestricted
+					VALID UNTIL 'infinity'
+					CONNECTION LIMIT UNLIMITED
+					SESSION TIMEOUT 120
+					EXTERNALID 'it''s-1'
+			`,
+			expected: `CREATE USER "bob" PASSWORD NULL NOCREATEDB SUPERUSER VALID UNTIL 'infinity' CONNECTION LIMIT -1;
+ALTER ROLE "bob" SET redshift.syslog_access = 'UNRESTRICTED';
+ALTER ROLE "bob" SET redshift.session_timeout = '120';
+ALTER ROLE "bob" SET redshift.external_id = 'it''s-1'`,
+		},
+		"create_user_nocreateuser": {
+			input:    `CREATE USER bob PASSWORD 'Secret123' NOCREATEUSER`,
+			expected: `CREATE USER bob PASSWORD 'Secret123' NOSUPERUSER`,
+		},
+		"alter_user_only_settings": {
+			input:    `ALTER USER "bob" RESET SESSION TIMEOUT`,
+			expected: `ALTER ROLE "bob" RESET redshift.session_timeout`,
+		},
+		"alter_user_rename": {
+			input:    `ALTER USER "bob" RENAME TO "robert"`,
+			expected: `ALTER USER "bob" RENAME TO "robert"`,
+		},
+		"create_role": {
+			input: `CREATE ROLE "analysts" EXTERNALID 'abc' `,
+			expected: `CREATE ROLE "analysts";
+ALTER ROLE "analysts" SET redshift.kind = 'role';
+ALTER ROLE "analysts" SET redshift.external_id = 'abc'`,
+		},
+		"alter_role_rename_and_external_id": {
+			input: `ALTER ROLE "analysts" RENAME TO "readers" EXTERNALID 'xyz'`,
+			expected: `ALTER ROLE "analysts" RENAME TO "readers";
+ALTER ROLE "readers" SET redshift.external_id = 'xyz'`,
+		},
+		"create_group_with_users": {
+			input:    `CREATE GROUP "admins" WITH USER "bob", "alice"`,
+			expected: `CREATE GROUP "admins" WITH ROLE "bob", "alice"`,
+		},
+		"alter_group": {
+			input:    `ALTER GROUP "admins" ADD USER "bob"`,
+			expected: `ALTER GROUP "admins" ADD USER "bob"`,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// the options keep the template's layout
+			assert.Equal(t, strings.Fields(test.expected), strings.Fields(Translate(test.input)))
+		})
+	}
+}
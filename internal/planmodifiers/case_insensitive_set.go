@@ -0,0 +1,84 @@
+package planmodifiers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ planmodifier.Set = caseInsensitiveSetModifier{}
+
+type caseInsensitiveSetModifier struct{}
+
+// Description describes the plan modification in plain text formatting.
+func (m caseInsensitiveSetModifier) Description(_ context.Context) string {
+	return "Names which only differ from the prior state by case are not planned as a change."
+}
+
+// MarkdownDescription describes the plan modification in Markdown formatting.
+func (m caseInsensitiveSetModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+// PlanModifySet keeps the prior state when it holds the same names as the
+// configuration, ignoring case.
+func (m caseInsensitiveSetModifier) PlanModifySet(ctx context.Context, req planmodifier.SetRequest, resp *planmodifier.SetResponse) {
+	// nothing to compare on create, or with values only known at apply
+	if req.StateValue.IsNull() || req.PlanValue.IsNull() || req.PlanValue.IsUnknown() {
+		return
+	}
+
+	if req.PlanValue.Equal(req.StateValue) {
+		return
+	}
+
+	var plan, state []types.String
+	resp.Diagnostics.Append(req.PlanValue.ElementsAs(ctx, &plan, false)...)
+	resp.Diagnostics.Append(req.StateValue.ElementsAs(ctx, &state, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !equalFold(plan, state) || !equalFold(state, plan) {
+		return
+	}
+
+	resp.PlanValue = req.StateValue
+}
+
+// true when every known name on the left has a case-insensitive match on the right.
+func equalFold(left []types.String, right []types.String) bool {
+	for _, l := range left {
+		if l.IsUnknown() {
+			return false
+		}
+
+		found := false
+		for _, r := range right {
+			if strings.EqualFold(l.ValueString(), r.ValueString()) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CaseInsensitiveSet returns a plan modifier for a set of identifiers which
+// suppresses differences in case only.
+//
+// Redshift folds identifiers to lower case unless enable_case_sensitive_identifier
+// is on, so the catalog reports `bob` for a configured `Bob`. The modifier has
+// no connection to check the setting, on a cluster with case sensitive
+// identifiers `Bob` and `bob` are different users and the resource's
+// ModifyPlan plans the configured names again.
+func CaseInsensitiveSet() planmodifier.Set {
+	return caseInsensitiveSetModifier{}
+}
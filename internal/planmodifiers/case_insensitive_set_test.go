@@ -0,0 +1,85 @@
+package planmodifiers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_CaseInsensitiveSet(t *testing.T) {
+	t.Parallel()
+
+	set := func(names ...string) types.Set {
+		elements := []attr.Value{}
+		for _, name := range names {
+			elements = append(elements, types.StringValue(name))
+		}
+		return types.SetValueMust(types.StringType, elements)
+	}
+
+	type testCase struct {
+		state    types.Set
+		plan     types.Set
+		expected types.Set
+	}
+	tests := map[string]testCase{
+		"create": {
+			state:    types.SetNull(types.StringType),
+			plan:     set("Bob"),
+			expected: set("Bob"),
+		},
+		"unknown": {
+			state:    set("bob"),
+			plan:     types.SetUnknown(types.StringType),
+			expected: types.SetUnknown(types.StringType),
+		},
+		"case_only": {
+			state:    set("bob", "alice"),
+			plan:     set("Alice", "BOB"),
+			expected: set("bob", "alice"),
+		},
+		"added": {
+			state:    set("bob"),
+			plan:     set("Bob", "alice"),
+			expected: set("Bob", "alice"),
+		},
+		"removed": {
+			state:    set("bob", "alice"),
+			plan:     set("Bob"),
+			expected: set("Bob"),
+		},
+		"unknown_element": {
+			state:    set("bob"),
+			plan:     types.SetValueMust(types.StringType, []attr.Value{types.StringUnknown()}),
+			expected: types.SetValueMust(types.StringType, []attr.Value{types.StringUnknown()}),
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := planmodifier.SetRequest{
+				StateValue: test.state,
+				PlanValue:  test.plan,
+			}
+			resp := planmodifier.SetResponse{
+				PlanValue: test.plan,
+			}
+
+			CaseInsensitiveSet().PlanModifySet(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("got unexpected error: %s", resp.Diagnostics)
+			}
+
+			if !resp.PlanValue.Equal(test.expected) {
+				t.Fatalf("expected %s, got %s", test.expected, resp.PlanValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/redshift"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_auditRecordsStatementsInTransaction(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeRedshift(t)
+	client := redshift.NewClient(fake.connConfig(t))
+	client.Audit = &redshift.Audit{Schema: "Audit", Table: "ddl_log"}
+	defer client.Close()
+
+	ctx := helpers.WithSubsystem(context.Background(), "user_resource.Create")
+
+	// as the provider does when configured
+	if err := client.CreateAuditTable(ctx); err != nil {
+		t.Fatalf("failed to create audit table: %s", err)
+	}
+
+	for _, statements := range [][]string{
+		{"SET LOCAL enable_case_sensitive_identifier TO true", `CREATE USER "bob" PASSWORD 'Secret123'`, "SELECT 1"},
+		// a transaction which changed nothing is not recorded
+		{"SELECT 1"},
+	} {
+		err := client.InTx(ctx, "CreateUser", func(tx redshift.Querier) error {
+			for _, statement := range statements {
+				if _, err := tx.Exec(ctx, statement); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to run transaction: %s", err)
+		}
+	}
+
+	// the service names the object it changes
+	groups, err := redshift.NewGroupService(helpers.WithSubsystem(context.Background(), "group_resource.Delete"), client)
+	if err != nil {
+		t.Fatalf("failed to create group service: %s", err)
+	}
+	if err := groups.DropGroup("Devs"); err != nil {
+		t.Fatalf("failed to drop group: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	var queries []string
+	for _, query := range fake.queries {
+		queries = append(queries, strings.Join(strings.Fields(query), " "))
+	}
+
+	if !assert.Len(t, queries, 16) {
+		return
+	}
+
+	// created once, before any transaction
+	assert.True(t, strings.HasPrefix(queries[0], `CREATE TABLE IF NOT EXISTS "audit"."ddl_log"`), queries[0])
+	assert.Contains(t, queries[0], "object_name VARCHAR(1024)")
+	assert.Equal(t, []string{"begin", "SET LOCAL enable_case_sensitive_identifier TO true", `CREATE USER "bob" PASSWORD 'Secret123'`, "SELECT 1"}, queries[1:5])
+
+	// only the statement which changed redshift, redacted, in the same transaction
+	insert := queries[5]
+	assert.True(t, strings.HasPrefix(insert, `INSERT INTO "audit"."ddl_log"`), insert)
+	assert.Contains(t, insert, "CURRENT_USER, 'Create', 'redshift_user', '', 'CreateUser', 'CREATE USER \"bob\" PASSWORD ''***'''")
+	assert.NotContains(t, insert, "Secret123")
+	assert.Equal(t, []string{"commit", "begin", "SELECT 1", "commit", "begin"}, queries[6:11])
+
+	insert = queries[14]
+	assert.True(t, strings.HasPrefix(insert, `INSERT INTO "audit"."ddl_log"`), insert)
+	assert.Contains(t, insert, "CURRENT_USER, 'Delete', 'redshift_group', 'Devs', 'DropGroup', 'DROP GROUP \"Devs\"'")
+	assert.Equal(t, "commit", queries[15])
+}
@@ -0,0 +1,376 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/redshift"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &columnGrantResource{}
+	_ resource.ResourceWithConfigure      = &columnGrantResource{}
+	_ resource.ResourceWithValidateConfig = &columnGrantResource{}
+	_ resource.ResourceWithImportState    = &columnGrantResource{}
+	_ resource.ResourceWithModifyPlan     = &columnGrantResource{}
+)
+
+func NewColumnGrantResource() resource.Resource {
+	return &columnGrantResource{}
+}
+
+type columnGrantResource struct {
+	Client redshift.Executor
+}
+
+func (r *columnGrantResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_column_grant"
+}
+
+func (r *columnGrantResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = withTimeouts(ctx, generated.ColumnGrantResourceSchema(ctx))
+}
+
+func (r *columnGrantResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan generated.ColumnGrantModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "column_grant_resource.Create")
+
+	timeout, diags := planTimeouts.Create(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ddl := redshift.AlterColumnGrantDDLParams{
+		ColumnGrantDDLParams: columnGrantParams(plan),
+		Grant:                columnGrantColumns(ctx, plan, &resp.Diagnostics),
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	svc, err := redshift.NewColumnGrantService(ctx, r.Client.ForDatabase(plan.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "NewColumnGrantService", "Create", err)
+		return
+	}
+
+	_, err = svc.AlterColumnGrant(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "AlterColumnGrant on service ColumnGrantService", "Create", err)
+		return
+	}
+
+	// Only set those undefaulted computed
+	plan.Id = types.StringValue(helpers.JoinId(ddl.SchemaName, ddl.TableName, ddl.GranteeType, ddl.Grantee))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+func (r *columnGrantResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
+	var state generated.ColumnGrantModel
+	var stateTimeouts timeouts.Value
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "column_grant_resource.Read")
+
+	timeout, diags := stateTimeouts.Read(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// id is schema_name|table_name|grantee_type|grantee
+	parts, err := helpers.SplitId(state.Id.ValueString(), 4)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("id"),
+			"Invalid Identifier",
+			"Expected an identifier of the form schema_name|table_name|grantee_type|grantee.\n\n"+err.Error(),
+		)
+		return
+	}
+
+	ddl := redshift.ColumnGrantDDLParams{
+		SchemaName:  parts[0],
+		TableName:   parts[1],
+		GranteeType: parts[2],
+		Grantee:     parts[3],
+	}
+
+	svc, err := redshift.NewColumnGrantService(ctx, r.Client.ForDatabase(state.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "NewColumnGrantService", "Read", err)
+		return
+	}
+
+	grant, err := svc.FindColumnGrant(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "FindColumnGrant on service ColumnGrantService", "Read", err)
+		return
+	}
+
+	state.SchemaName = types.StringValue(grant.SchemaName)
+	state.TableName = types.StringValue(grant.TableName)
+	state.GranteeType = types.StringValue(grant.GranteeType)
+	if grant.GranteeType == "public" {
+		state.Grantee = types.StringNull()
+	} else {
+		state.Grantee = types.StringValue(grant.Grantee)
+	}
+
+	// redshift folds the column names, keep their configured spelling
+	prior := columnGrantColumns(ctx, state, &resp.Diagnostics)
+	state.SelectColumns = helpers.SetValueOrNull[string](ctx, types.StringType, helpers.ReconcileIdentifiers(prior["SELECT"], grant.Columns["SELECT"]), &resp.Diagnostics)
+	state.UpdateColumns = helpers.SetValueOrNull[string](ctx, types.StringType, helpers.ReconcileIdentifiers(prior["UPDATE"], grant.Columns["UPDATE"]), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &state, stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *columnGrantResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state generated.ColumnGrantModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "column_grant_resource.Update")
+
+	timeout, diags := planTimeouts.Update(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	planColumns := columnGrantColumns(ctx, plan, &resp.Diagnostics)
+	stateColumns := columnGrantColumns(ctx, state, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ddl := redshift.AlterColumnGrantDDLParams{
+		ColumnGrantDDLParams: columnGrantParams(state),
+		Grant:                map[string][]string{},
+		Revoke:               map[string][]string{},
+	}
+
+	for _, privilege := range []string{"SELECT", "UPDATE"} {
+		// if plan has more, those are granted
+		ddl.Grant[privilege] = helpers.MissingFrom(planColumns[privilege], stateColumns[privilege])
+
+		// if state has more, those are revoked
+		ddl.Revoke[privilege] = helpers.MissingFrom(stateColumns[privilege], planColumns[privilege])
+	}
+
+	svc, err := redshift.NewColumnGrantService(ctx, r.Client.ForDatabase(state.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "NewColumnGrantService", "Update", err)
+		return
+	}
+
+	_, err = svc.AlterColumnGrant(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "AlterColumnGrant on service ColumnGrantService", "Update", err)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+func (r *columnGrantResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state generated.ColumnGrantModel
+	var stateTimeouts timeouts.Value
+
+	// Read Terraform prior state into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "column_grant_resource.Delete")
+
+	timeout, diags := stateTimeouts.Delete(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ddl := redshift.AlterColumnGrantDDLParams{
+		ColumnGrantDDLParams: columnGrantParams(state),
+		Revoke:               columnGrantColumns(ctx, state, &resp.Diagnostics),
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	svc, err := redshift.NewColumnGrantService(ctx, r.Client.ForDatabase(state.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "NewColumnGrantService", "Delete", err)
+		return
+	}
+
+	_, err = svc.AlterColumnGrant(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "AlterColumnGrant on service ColumnGrantService", "Delete", err)
+		return
+	}
+
+	// If the logic reaches here, it implicitly succeeded and will remove
+	// the resource from state if there are no other errors
+}
+
+func (r *columnGrantResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateInDatabase(ctx, req, resp)
+}
+
+func (r *columnGrantResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Type",
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.Client = data.Client
+}
+
+func (r *columnGrantResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var plan generated.ColumnGrantModel
+
+	resp.Diagnostics.Append(getModel(ctx, req.Config, &plan, nil)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.SelectColumns.IsNull() && plan.UpdateColumns.IsNull() {
+		resp.Diagnostics.AddError(
+			"Missing Attribute Configuration",
+			"At least one of select_columns or update_columns must be set.",
+		)
+	}
+
+	// an empty set would be read back as null
+	if !plan.SelectColumns.IsNull() && !plan.SelectColumns.IsUnknown() && len(plan.SelectColumns.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("select_columns"),
+			"Invalid Attribute Value",
+			"select_columns must contain at least one column, omit it instead.",
+		)
+	}
+
+	if !plan.UpdateColumns.IsNull() && !plan.UpdateColumns.IsUnknown() && len(plan.UpdateColumns.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("update_columns"),
+			"Invalid Attribute Value",
+			"update_columns must contain at least one column, omit it instead.",
+		)
+	}
+
+	if plan.GranteeType.IsNull() || plan.GranteeType.IsUnknown() || plan.Grantee.IsUnknown() {
+		return
+	}
+
+	if plan.GranteeType.ValueString() == "public" && !plan.Grantee.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("grantee"),
+			"Invalid Attribute Combination",
+			"grantee must be omitted when grantee_type is public.",
+		)
+	}
+
+	if plan.GranteeType.ValueString() != "public" && plan.Grantee.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("grantee"),
+			"Missing Attribute Configuration",
+			fmt.Sprintf("grantee must be set when grantee_type is %s.", plan.GranteeType.ValueString()),
+		)
+	}
+}
+
+func columnGrantParams(model generated.ColumnGrantModel) redshift.ColumnGrantDDLParams {
+	return redshift.ColumnGrantDDLParams{
+		SchemaName:  model.SchemaName.ValueString(),
+		TableName:   model.TableName.ValueString(),
+		GranteeType: model.GranteeType.ValueString(),
+		Grantee:     model.Grantee.ValueString(),
+	}
+}
+
+// the configured columns keyed by privilege.
+func columnGrantColumns(ctx context.Context, model generated.ColumnGrantModel, diags *diag.Diagnostics) map[string][]string {
+	var selects, updates []string
+
+	if !model.SelectColumns.IsNull() && !model.SelectColumns.IsUnknown() {
+		diags.Append(model.SelectColumns.ElementsAs(ctx, &selects, false)...)
+	}
+
+	if !model.UpdateColumns.IsNull() && !model.UpdateColumns.IsUnknown() {
+		diags.Append(model.UpdateColumns.ElementsAs(ctx, &updates, false)...)
+	}
+
+	return map[string][]string{
+		"SELECT": selects,
+		"UPDATE": updates,
+	}
+}
+
+func (r *columnGrantResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// the plan needs no change, only to wait for the provider configuration
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+}
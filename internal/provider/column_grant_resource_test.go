@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"terraform-provider-redshift/internal/helpers"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccColumnGrant_user(t *testing.T) {
+	testAccRequiresRedshift(t)
+
+	suffix := strings.ToLower(acctest.RandStringFromCharSet(10, helpers.CharSetAlpha))
+	table := "tst_column_grant_table" + suffix
+	user1 := "tst_user1" + suffix
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, fmt.Sprintf("CREATE TABLE public.%s (id integer, name varchar(64), secret varchar(64))", table))
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			testAccExec(t, fmt.Sprintf("DROP TABLE public.%s", table))
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_user" "test1" {
+					name = "%s"
+				}
+				resource "redshift_column_grant" "under_test" {
+					table_name     = "%s"
+					grantee_type   = "user"
+					grantee        = redshift_user.test1.name
+					select_columns = ["id", "name"]
+				}
+				`, user1, table),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_column_grant.under_test", "id", helpers.JoinId("public", table, "user", user1)),
+					resource.TestCheckResourceAttr("redshift_column_grant.under_test", "select_columns.#", "2"),
+					resource.TestCheckTypeSetElemAttr("redshift_column_grant.under_test", "select_columns.*", "id"),
+					resource.TestCheckTypeSetElemAttr("redshift_column_grant.under_test", "select_columns.*", "name"),
+					resource.TestCheckNoResourceAttr("redshift_column_grant.under_test", "update_columns"),
+				),
+			},
+			{
+				ResourceName:      "redshift_column_grant.under_test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_user" "test1" {
+					name = "%s"
+				}
+				resource "redshift_column_grant" "under_test" {
+					table_name     = "%s"
+					grantee_type   = "user"
+					grantee        = redshift_user.test1.name
+					select_columns = ["id"]
+					update_columns = ["name"]
+				}
+				`, user1, table),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_column_grant.under_test", "select_columns.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_column_grant.under_test", "select_columns.*", "id"),
+					resource.TestCheckResourceAttr("redshift_column_grant.under_test", "update_columns.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_column_grant.under_test", "update_columns.*", "name"),
+				),
+			},
+			{
+				// redshift folds the names, the configured spelling is kept
+				// without a diff
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_user" "test1" {
+					name = "%s"
+				}
+				resource "redshift_column_grant" "under_test" {
+					table_name     = "%s"
+					grantee_type   = "user"
+					grantee        = redshift_user.test1.name
+					select_columns = ["ID"]
+					update_columns = ["Name"]
+				}
+				`, user1, table),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr("redshift_column_grant.under_test", "select_columns.*", "ID"),
+					resource.TestCheckTypeSetElemAttr("redshift_column_grant.under_test", "update_columns.*", "Name"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"fmt"
+	"net"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/redshift"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// connConfig builds the pgx config from the connection attributes, or from
+// dsn when it is set. Values are assigned to their fields rather than
+// rendered into a connection string, so they need no quoting.
+//
+// pgx fills whatever a connection string leaves out from the PG* environment
+// variables, as libpq does. That is kept for a dsn, but without one the
+// attributes alone configure the connection and the environment is never read.
+func connConfig(cfg *generated.RedshiftModel, defaultApplicationName string) (*pgx.ConnConfig, error) {
+	var connCfg *pgx.ConnConfig
+	var err error
+	if cfg.Dsn.IsNull() {
+		err = redshift.WithoutEnvironment(func() (err error) {
+			connCfg, err = pgx.ParseConfig("")
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("connConfig: Failed to create config: %w", err)
+		}
+	} else {
+		err = redshift.WithEnvironment(func() (err error) {
+			connCfg, err = pgx.ParseConfig(cfg.Dsn.ValueString())
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("connConfig: Failed to parse dsn: %w", err)
+		}
+	}
+
+	if !cfg.Dsn.IsNull() && !cfg.SslRootCert.IsNull() {
+		verifyRequiredChain(connCfg)
+	}
+
+	if cfg.Dsn.IsNull() {
+		// the defaults pgx uses when no connect_timeout is given
+		connCfg.DialFunc = (&net.Dialer{KeepAlive: 5 * time.Minute}).DialContext
+		connCfg.LookupFunc = net.DefaultResolver.LookupHost
+
+		connCfg.Host = cfg.Host.ValueString()
+		connCfg.Port = uint16(cfg.Port.ValueInt64())
+		connCfg.User = cfg.Username.ValueString()
+		connCfg.Password = cfg.Password.ValueString()
+		connCfg.Database = cfg.Dbname.ValueString()
+
+		sslmode := cfg.Sslmode.ValueString()
+		if sslmode == "" {
+			sslmode = "prefer"
+		}
+
+		// allow and prefer fall back to a second attempt on the same host
+		tlsConfigs := tlsConfigs(sslmode, connCfg.Host, !cfg.SslRootCert.IsNull())
+		connCfg.TLSConfig = tlsConfigs[0]
+		connCfg.Fallbacks = nil
+		for _, tlsConfig := range tlsConfigs[1:] {
+			connCfg.Fallbacks = append(connCfg.Fallbacks, &pgconn.FallbackConfig{
+				Host:      connCfg.Host,
+				Port:      connCfg.Port,
+				TLSConfig: tlsConfig,
+			})
+		}
+	}
+
+	switch {
+	case !cfg.ApplicationName.IsNull():
+		connCfg.RuntimeParams["application_name"] = cfg.ApplicationName.ValueString()
+	case connCfg.RuntimeParams["application_name"] == "":
+		connCfg.RuntimeParams["application_name"] = defaultApplicationName
+	}
+
+	// timeout is the deprecated name of connect_timeout
+	connectTimeout := cfg.ConnectTimeout
+	if connectTimeout.IsNull() {
+		connectTimeout = cfg.Timeout
+	}
+	if connectTimeout.ValueInt64() > 0 {
+		connCfg.ConnectTimeout = time.Duration(connectTimeout.ValueInt64()) * time.Second
+	}
+
+	return connCfg, nil
+}
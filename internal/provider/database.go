@@ -0,0 +1,24 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// importStateInDatabase imports an identifier which may end in @database, for
+// objects outside the database the provider connects to.
+func importStateInDatabase(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id := req.ID
+
+	if i := strings.LastIndex(id, "@"); i >= 0 {
+		if database := id[i+1:]; database != "" {
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), database)...)
+		}
+		id = id[:i]
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
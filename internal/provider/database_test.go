@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-redshift/internal/generated"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_importStateInDatabase(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := generated.OwnerResourceSchema(ctx)
+
+	tests := map[string]struct {
+		id               string
+		expectedId       string
+		expectedDatabase string
+	}{
+		"default_database": {
+			id:         "table|public|events|",
+			expectedId: "table|public|events|",
+		},
+		"other_database": {
+			id:               "table|public|events|@analytics",
+			expectedId:       "table|public|events|",
+			expectedDatabase: "analytics",
+		},
+		"empty_database": {
+			id:         "schema||reporting|@",
+			expectedId: "schema||reporting|",
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &resource.ImportStateResponse{
+				State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)},
+			}
+			importStateInDatabase(ctx, resource.ImportStateRequest{ID: test.id}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+
+			var state generated.OwnerModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+
+			assert.Equal(t, test.expectedId, state.Id.ValueString())
+			assert.Equal(t, test.expectedDatabase, state.Database.ValueString())
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+	"terraform-provider-redshift/internal/redshift"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// Reports an error from calling a service. Errors redshift raised about the
+// object itself are scoped to attr, the attribute naming it, and explain what
+// to do about them; anything else keeps the generic wording.
+func addServiceError(diags *diag.Diagnostics, attr path.Path, call string, operation string, err error) {
+	var unknown unknownConfigError
+	if errors.As(err, &unknown) {
+		diags.AddError(
+			"Provider Configuration Not Known",
+			"The provider attributes "+strings.Join(unknown.attributes, ", ")+" are not known until apply, "+
+				"such as the endpoint of a cluster created in the same run, so "+call+" could not reach redshift. "+
+				"Apply what they depend on first, for example with -target, then apply again.\n\n"+
+				"Unable to "+operation+": "+err.Error(),
+		)
+		return
+	}
+
+	if errors.Is(err, redshift.ErrReadOnly) {
+		diags.AddError(
+			"Provider Is Read Only",
+			"read_only is set on the provider, so "+call+" was refused before it changed anything in redshift. "+
+				"Apply the change from a workspace whose provider is not read only.\n\n"+
+				"Unable to "+operation+": "+err.Error(),
+		)
+		return
+	}
+
+	if errors.Is(err, redshift.ErrSQLOutputOnly) {
+		diags.AddError(
+			"Statements Not Run",
+			"sql_output_only is set on the provider, so the statements of "+call+" were written to sql_output_file instead of being run. "+
+				"Nothing changed in redshift and the state is left as it was.\n\n"+
+				"Unable to "+operation+": "+err.Error(),
+		)
+		return
+	}
+
+	kind, pgErr := redshift.ClassifyError(err)
+
+	var summary, hint string
+	switch kind {
+	case redshift.ErrorKindDuplicateObject:
+		summary = "Object Already Exists"
+		hint = "The object already exists in redshift. Import it into the state with terraform import, or choose another name."
+	case redshift.ErrorKindInsufficientPrivilege:
+		summary = "Insufficient Privilege"
+		hint = "The provider's user lacks the privilege for this statement. Grant it, or connect as a superuser."
+		attr = path.Empty()
+	case redshift.ErrorKindDependentObjects:
+		summary = "Object Has Dependents"
+		hint = "Other objects depend on this one, or it still owns objects or holds privileges. " +
+			"Drop or reassign them first, e.g. with redshift_owner, then retry."
+	case redshift.ErrorKindInvalidPassword:
+		summary = "Invalid Password"
+		hint = "The password was rejected. Redshift requires 8 to 64 characters with an upper case letter, " +
+			"a lower case letter and a number, or an md5/sha256 hash."
+		attr = path.Root("password")
+	case redshift.ErrorKindAuthenticationFailed:
+		summary = "Authentication Failed"
+		hint = "Redshift refused the provider's own login. Check the username and password, dsn or serverless credentials " +
+			"the provider is configured with."
+		attr = path.Empty()
+	case redshift.ErrorKindUndefinedObject:
+		summary = "Object Does Not Exist"
+		hint = "A referenced object does not exist in redshift. Create it first, or check the name and its case."
+	default:
+		diags.AddError(
+			"Failed to execute "+call,
+			"An unexpected error occurred when calling "+call+". "+
+				"If the error is not clear, please contact the provider developers.\n\n"+
+				"Unable to "+operation+": "+err.Error(),
+		)
+		return
+	}
+
+	detail := hint + "\n\nUnable to " + operation + ": " + pgErr.Message
+	if pgErr.Detail != "" {
+		detail += "\n\nDetail: " + pgErr.Detail
+	}
+	if pgErr.Hint != "" {
+		detail += "\n\nHint: " + pgErr.Hint
+	}
+	detail += "\n\nSQLSTATE " + pgErr.Code + " from " + call + "."
+
+	if attr.Equal(path.Empty()) {
+		diags.AddError(summary, detail)
+		return
+	}
+
+	diags.AddAttributeError(attr, summary, detail)
+}
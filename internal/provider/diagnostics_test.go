@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"terraform-provider-redshift/internal/redshift"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func Test_addServiceError(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		err             error
+		expectedSummary string
+		expectedPath    path.Path
+		expectedDetail  string
+	}
+	tests := map[string]testCase{
+		"unknown": {
+			err:             errors.New("CreateUser: Unable to connect dial tcp: connection refused"),
+			expectedSummary: "Failed to execute CreateUser on service UserService",
+			expectedDetail:  "Unable to Create: CreateUser: Unable to connect dial tcp: connection refused",
+		},
+		"duplicate_object": {
+			err:             fmt.Errorf("CreateUser: Failed to execute: %w", &pgconn.PgError{Code: "42710", Message: `user "bob" already exists`}),
+			expectedSummary: "Object Already Exists",
+			expectedPath:    path.Root("name"),
+			expectedDetail:  `Unable to Create: user "bob" already exists`,
+		},
+		"insufficient_privilege": {
+			err:             &pgconn.PgError{Code: "42501", Message: "permission denied to create user"},
+			expectedSummary: "Insufficient Privilege",
+			expectedDetail:  "SQLSTATE 42501",
+		},
+		"read_only": {
+			err:             fmt.Errorf("CreateUser: Failed to execute: %w", fmt.Errorf("%w, refusing to run CREATE", redshift.ErrReadOnly)),
+			expectedSummary: "Provider Is Read Only",
+			expectedDetail:  "Unable to Create: CreateUser: Failed to execute: the provider is read only, refusing to run CREATE",
+		},
+		"sql_output_only": {
+			err:             fmt.Errorf("CreateUser: %w", redshift.ErrSQLOutputOnly),
+			expectedSummary: "Statements Not Run",
+			expectedDetail:  "written to sql_output_file instead of being run",
+		},
+		"unknown_config": {
+			err:             unknownConfig{attributes: []string{"host", "ssh_tunnel.host"}}.InTx(context.Background(), "CreateUser", nil),
+			expectedSummary: "Provider Configuration Not Known",
+			expectedDetail:  "The provider attributes host, ssh_tunnel.host are not known until apply",
+		},
+		"authentication_failed": {
+			err:             fmt.Errorf("CreateUser: Unable to connect: %w", &pgconn.PgError{Code: "28P01", Message: `password authentication failed for user "admin"`}),
+			expectedSummary: "Authentication Failed",
+			expectedDetail:  "refused the provider's own login",
+		},
+		"invalid_password": {
+			err:             &pgconn.PgError{Code: "22023", Message: "Password must contain a number.", Hint: "use a number"},
+			expectedSummary: "Invalid Password",
+			expectedPath:    path.Root("password"),
+			expectedDetail:  "Hint: use a number",
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var diags diag.Diagnostics
+			addServiceError(&diags, path.Root("name"), "CreateUser on service UserService", "Create", test.err)
+
+			if diags.ErrorsCount() != 1 {
+				t.Fatalf("expected one error, got %d", diags.ErrorsCount())
+			}
+
+			d := diags.Errors()[0]
+			if d.Summary() != test.expectedSummary {
+				t.Errorf("expected summary %q, got %q", test.expectedSummary, d.Summary())
+			}
+
+			if !strings.Contains(d.Detail(), test.expectedDetail) {
+				t.Errorf("expected detail to contain %q, got %q", test.expectedDetail, d.Detail())
+			}
+
+			withPath, ok := d.(diag.DiagnosticWithPath)
+			if len(test.expectedPath.Steps()) == 0 {
+				if ok {
+					t.Errorf("expected no attribute, got %s", withPath.Path())
+				}
+				return
+			}
+
+			if !ok || !withPath.Path().Equal(test.expectedPath) {
+				t.Errorf("expected attribute %s", test.expectedPath)
+			}
+		})
+	}
+}
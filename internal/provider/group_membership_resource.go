@@ -0,0 +1,448 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/redshift"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &groupMembershipResource{}
+	_ resource.ResourceWithConfigure      = &groupMembershipResource{}
+	_ resource.ResourceWithValidateConfig = &groupMembershipResource{}
+	_ resource.ResourceWithImportState    = &groupMembershipResource{}
+	_ resource.ResourceWithModifyPlan     = &groupMembershipResource{}
+)
+
+func NewGroupMembershipResource() resource.Resource {
+	return &groupMembershipResource{}
+}
+
+// Unlike redshift_group.usernames, only the declared users are managed, so
+// several memberships can share one group; the id is the group name and the
+// usernames. Adding a user who already belongs to the group fails the plan,
+// as another membership may manage them. A group which also sets usernames
+// drops these users again on its next apply, which shows up here as a warning.
+type groupMembershipResource struct {
+	Client redshift.Executor
+}
+
+func (r *groupMembershipResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_membership"
+}
+
+func (r *groupMembershipResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = withTimeouts(ctx, generated.GroupMembershipResourceSchema(ctx))
+}
+
+func (r *groupMembershipResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan generated.GroupMembershipModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "group_membership_resource.Create")
+
+	timeout, diags := planTimeouts.Create(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var usernames []string
+	resp.Diagnostics.Append(plan.Usernames.ElementsAs(ctx, &usernames, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ddl := redshift.AlterGroupDDLParams{
+		Name: plan.GroupName.ValueString(),
+		Add:  &usernames,
+	}
+
+	svc, err := redshift.NewGroupService(ctx, r.Client)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("group_name"), "NewGroupService", "Create", err)
+		return
+	}
+
+	err = svc.AlterGroup(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("group_name"), "AlterGroup on service GroupService", "Create", err)
+		return
+	}
+
+	// Only set those undefaulted computed
+	plan.Id = types.StringValue(membershipId(plan.GroupName.ValueString(), usernames))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+func (r *groupMembershipResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
+	var state generated.GroupMembershipModel
+	var stateTimeouts timeouts.Value
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "group_membership_resource.Read")
+
+	timeout, diags := stateTimeouts.Read(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	svc, err := redshift.NewGroupService(ctx, r.Client)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("group_name"), "NewGroupService", "Read", err)
+		return
+	}
+
+	groupName, declared := splitMembershipId(state.Id.ValueString())
+
+	group, err := svc.FindGroupByName(groupName)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("group_name"), "FindGroupByName on service GroupService", "Read", err)
+		return
+	}
+
+	// on import the declared users come from the id, never from the other
+	// members of the group
+	if !state.Usernames.IsNull() {
+		declared = nil
+		resp.Diagnostics.Append(state.Usernames.ElementsAs(ctx, &declared, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// match the members case insensitively, redshift may have folded the case
+	members := helpers.ReconcileIdentifiers(declared, *group.Users)
+	missing := helpers.MissingFrom(declared, members)
+	if len(missing) > 0 {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("usernames"),
+			"Group Membership Removed Outside Of This Resource",
+			fmt.Sprintf("The user(s) %s are no longer in group %s and will be added again. ", strings.Join(missing, ", "), group.Name)+
+				"If the redshift_group for this group also sets usernames, it removes them on every apply; "+
+				"omit usernames on the group or add these users to it instead.",
+		)
+	}
+
+	state.Id = types.StringValue(membershipId(groupName, declared))
+	state.GroupName = types.StringValue(group.Name)
+	state.Usernames = helpers.SetValueOrNull[string](ctx, types.StringType, helpers.MissingFrom(declared, missing), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &state, stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *groupMembershipResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state generated.GroupMembershipModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "group_membership_resource.Update")
+
+	timeout, diags := planTimeouts.Update(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var plan_usernames, state_usernames []string
+
+	resp.Diagnostics.Append(plan.Usernames.ElementsAs(ctx, &plan_usernames, false)...)
+	if !state.Usernames.IsNull() {
+		resp.Diagnostics.Append(state.Usernames.ElementsAs(ctx, &state_usernames, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// a change of case alone is left to the service, which matches users as
+	// the cluster folds their names
+
+	// if plan has more, those are add
+	adds := helpers.MissingFrom(plan_usernames, state_usernames)
+
+	// if state has more, those are drop
+	drops := helpers.MissingFrom(state_usernames, plan_usernames)
+
+	ddl := redshift.AlterGroupDDLParams{
+		Name: state.GroupName.ValueString(),
+		Add:  &adds,
+		Drop: &drops,
+	}
+
+	svc, err := redshift.NewGroupService(ctx, r.Client)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("group_name"), "NewGroupService", "Update", err)
+		return
+	}
+
+	err = svc.AlterGroup(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("group_name"), "AlterGroup on service GroupService", "Update", err)
+		return
+	}
+
+	// ModifyPlan leaves the id unknown when the users change
+	if plan.Id.IsUnknown() {
+		plan.Id = types.StringValue(membershipId(plan.GroupName.ValueString(), plan_usernames))
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+func (r *groupMembershipResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state generated.GroupMembershipModel
+	var stateTimeouts timeouts.Value
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "group_membership_resource.Delete")
+
+	timeout, diags := stateTimeouts.Delete(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var usernames []string
+	if !state.Usernames.IsNull() {
+		resp.Diagnostics.Append(state.Usernames.ElementsAs(ctx, &usernames, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	ddl := redshift.AlterGroupDDLParams{
+		Name: state.GroupName.ValueString(),
+		Drop: &usernames,
+	}
+
+	svc, err := redshift.NewGroupService(ctx, r.Client)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("group_name"), "NewGroupService", "Delete", err)
+		return
+	}
+
+	err = svc.AlterGroup(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("group_name"), "AlterGroup on service GroupService", "Delete", err)
+		return
+	}
+}
+
+func (r *groupMembershipResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// only the named users are adopted, the group's other members may
+	// belong to other resources
+	if _, usernames := splitMembershipId(req.ID); len(usernames) == 0 || slices.Contains(usernames, "") {
+		resp.Diagnostics.AddError(
+			"Invalid Import Id",
+			fmt.Sprintf("Expected the group name and the usernames of the membership separated by commas, such as devs%salice,bob, got: %s. "+
+				"A username containing a comma cannot be part of a membership.", helpers.IdSeparator, req.ID),
+		)
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *groupMembershipResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Type",
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.Client = data.Client
+}
+
+func (r *groupMembershipResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var plan generated.GroupMembershipModel
+
+	resp.Diagnostics.Append(getModel(ctx, req.Config, &plan, nil)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Usernames.IsNull() || plan.Usernames.IsUnknown() {
+		return
+	}
+
+	var usernames []types.String
+	resp.Diagnostics.Append(plan.Usernames.ElementsAs(ctx, &usernames, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// commas separate the usernames in the id
+	for _, username := range usernames {
+		if strings.Contains(username.ValueString(), ",") {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("usernames").AtSetValue(username),
+				"Invalid Username",
+				fmt.Sprintf("%q contains a comma, which separates the usernames in the id of a membership. Manage this user with redshift_group.usernames instead.", username.ValueString()),
+			)
+		}
+	}
+}
+
+func (r *groupMembershipResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
+	// nothing to check on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan generated.GroupMembershipModel
+
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "group_membership_resource.ModifyPlan")
+
+	var state *generated.GroupMembershipModel
+	if !req.State.Raw.IsNull() {
+		state = &generated.GroupMembershipModel{}
+
+		resp.Diagnostics.Append(getModel(ctx, req.State, state, nil)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// the cluster cannot be checked before the provider is configured
+	configured := clientConfigured(r.Client)
+
+	if state != nil {
+		if configured {
+			plan.Usernames = planCaseSensitiveUsernames(ctx, r.Client, req.Config, &resp.Plan, plan.Usernames, &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		// the id names the users, it changes with them
+		if !plan.Usernames.Equal(state.Usernames) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+		}
+	}
+
+	if !configured || plan.GroupName.IsUnknown() || plan.Usernames.IsUnknown() {
+		return
+	}
+
+	var elements []types.String
+	resp.Diagnostics.Append(plan.Usernames.ElementsAs(ctx, &elements, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var declared []string
+	if state != nil && !state.Usernames.IsNull() {
+		resp.Diagnostics.Append(state.Usernames.ElementsAs(ctx, &declared, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// users named by another resource are not known until apply, and those
+	// already declared here are this membership's own
+	var adds []string
+	for _, element := range elements {
+		if element.IsUnknown() || slices.ContainsFunc(declared, func(d string) bool { return strings.EqualFold(d, element.ValueString()) }) {
+			continue
+		}
+		adds = append(adds, element.ValueString())
+	}
+
+	checkMembersUnclaimed(ctx, r.Client, plan.GroupName.ValueString(), adds, &resp.Diagnostics)
+}
+
+// membershipId joins the group name and the sorted usernames, so memberships
+// of the same group are told apart.
+func membershipId(groupName string, usernames []string) string {
+	sorted := slices.Clone(usernames)
+	slices.Sort(sorted)
+
+	return helpers.JoinId(groupName, strings.Join(sorted, ","))
+}
+
+// splitMembershipId returns the group name and usernames of an id. An id
+// from before the usernames were part of it is only the group name.
+func splitMembershipId(id string) (string, []string) {
+	groupName, usernames, ok := strings.Cut(id, helpers.IdSeparator)
+	if !ok || usernames == "" {
+		return groupName, nil
+	}
+
+	return groupName, strings.Split(usernames, ",")
+}
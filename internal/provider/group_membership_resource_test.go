@@ -0,0 +1,326 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/redshift"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	fwtypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccGroupMembership_shared(t *testing.T) {
+	suffix := strings.ToLower(acctest.RandStringFromCharSet(10, helpers.CharSetAlpha))
+	group1 := "tst_group1" + suffix
+	user1 := "tst_user1" + suffix
+	user2 := "tst_user2" + suffix
+	user3 := "tst_user3" + suffix
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// two memberships add to the same group without fighting
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_user" "test1" {
+					name = "%s"
+				}
+				resource "redshift_user" "test2" {
+					name = "%s"
+				}
+				resource "redshift_group" "test" {
+					name = "%s"
+				}
+				resource "redshift_group_membership" "first" {
+					group_name = redshift_group.test.name
+					usernames  = [ redshift_user.test1.name ]
+				}
+				resource "redshift_group_membership" "second" {
+					group_name = redshift_group.test.name
+					usernames  = [ redshift_user.test2.name ]
+				}
+				`, user1, user2, group1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_group_membership.first", "id", group1+"|"+user1),
+					resource.TestCheckResourceAttr("redshift_group_membership.first", "usernames.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_group_membership.first", "usernames.*", user1),
+					resource.TestCheckResourceAttr("redshift_group_membership.second", "usernames.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_group_membership.second", "usernames.*", user2),
+				),
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_user" "test1" {
+					name = "%s"
+				}
+				resource "redshift_user" "test2" {
+					name = "%s"
+				}
+				resource "redshift_user" "test3" {
+					name = "%s"
+				}
+				resource "redshift_group" "test" {
+					name = "%s"
+				}
+				resource "redshift_group_membership" "first" {
+					group_name = redshift_group.test.name
+					usernames  = [ redshift_user.test3.name ]
+				}
+				resource "redshift_group_membership" "second" {
+					group_name = redshift_group.test.name
+					usernames  = [ redshift_user.test2.name ]
+				}
+				`, user1, user2, user3, group1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_group_membership.first", "usernames.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_group_membership.first", "usernames.*", user3),
+					resource.TestCheckResourceAttr("redshift_group_membership.second", "usernames.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_group_membership.second", "usernames.*", user2),
+				),
+			},
+			// only the users in the id are adopted, not those of first
+			{
+				ResourceName:      "redshift_group_membership.second",
+				ImportState:       true,
+				ImportStateId:     group1 + "|" + user2,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func Test_groupMembershipModifyPlanCaseSensitive(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		setting  string
+		expected []string
+	}{
+		// the case insensitive set modifier kept the prior spelling
+		"case_insensitive": {setting: "off", expected: []string{"Alice"}},
+		// alice is another user than Alice
+		"case_sensitive": {setting: "on", expected: []string{"alice"}},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			fake := newFakeRedshift(t)
+			fake.settings = map[string]string{"enable_case_sensitive_identifier": test.setting}
+			client := redshift.NewClient(fake.connConfig(t))
+			defer client.Close()
+
+			r := &groupMembershipResource{Client: client}
+
+			schemaResp := &fwresource.SchemaResponse{}
+			r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+			model := func(usernames ...string) *generated.GroupMembershipModel {
+				set, diags := fwtypes.SetValueFrom(ctx, fwtypes.StringType, usernames)
+				if diags.HasError() {
+					t.Fatalf("failed to build usernames: %v", diags)
+				}
+				return &generated.GroupMembershipModel{
+					GroupName: fwtypes.StringValue("devs"),
+					Id:        fwtypes.StringValue("devs"),
+					Usernames: set,
+				}
+			}
+			state := testState(t, ctx, schemaResp.Schema, model("Alice"))
+			config := testState(t, ctx, schemaResp.Schema, model("alice"))
+
+			resp := &fwresource.ModifyPlanResponse{
+				Plan: tfsdk.Plan{Schema: state.Schema, Raw: state.Raw.Copy()},
+			}
+			r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: config.Schema, Raw: config.Raw},
+				Plan:   tfsdk.Plan{Schema: state.Schema, Raw: state.Raw.Copy()},
+				State:  state,
+			}, resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("got unexpected error: %v", resp.Diagnostics)
+			}
+
+			var plan generated.GroupMembershipModel
+			resp.Diagnostics.Append(getModel(ctx, resp.Plan, &plan, nil)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("failed to read plan: %v", resp.Diagnostics)
+			}
+
+			var usernames []string
+			resp.Diagnostics.Append(plan.Usernames.ElementsAs(ctx, &usernames, false)...)
+
+			assert.Equal(t, test.expected, usernames)
+		})
+	}
+}
+
+func Test_membershipId(t *testing.T) {
+	t.Parallel()
+
+	id := membershipId("devs", []string{"bob", "alice"})
+	assert.Equal(t, "devs|alice,bob", id)
+
+	groupName, usernames := splitMembershipId(id)
+	assert.Equal(t, "devs", groupName)
+	assert.Equal(t, []string{"alice", "bob"}, usernames)
+
+	// an id from before the usernames were part of it
+	groupName, usernames = splitMembershipId("devs")
+	assert.Equal(t, "devs", groupName)
+	assert.Empty(t, usernames)
+}
+
+func Test_groupMembershipImportState(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := &groupMembershipResource{}
+
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	tests := map[string]struct {
+		id          string
+		expectedErr bool
+	}{
+		"group_and_users": {id: "devs|alice,bob"},
+		// the other members of the group may belong to other resources
+		"group_only": {id: "devs", expectedErr: true},
+		"no_users":   {id: "devs|", expectedErr: true},
+		// usernames cannot contain commas, they separate them
+		"empty_username": {id: "devs|alice,,bob", expectedErr: true},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &fwresource.ImportStateResponse{
+				State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)},
+			}
+			r.ImportState(ctx, fwresource.ImportStateRequest{ID: test.id}, resp)
+
+			assert.Equal(t, test.expectedErr, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+		})
+	}
+}
+
+func Test_groupMembershipValidateConfig(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := &groupMembershipResource{}
+
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	tests := map[string]struct {
+		usernames   []string
+		expectedErr bool
+	}{
+		"usernames": {usernames: []string{"alice", "bob"}},
+		"comma":     {usernames: []string{"alice", "smith,bob"}, expectedErr: true},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			usernames, diags := fwtypes.SetValueFrom(ctx, fwtypes.StringType, test.usernames)
+			if diags.HasError() {
+				t.Fatalf("failed to build usernames: %v", diags)
+			}
+
+			state := testState(t, ctx, schemaResp.Schema, &generated.GroupMembershipModel{
+				GroupName: fwtypes.StringValue("devs"),
+				Id:        fwtypes.StringNull(),
+				Usernames: usernames,
+			})
+
+			resp := &fwresource.ValidateConfigResponse{}
+			r.ValidateConfig(ctx, fwresource.ValidateConfigRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}, resp)
+
+			assert.Equal(t, test.expectedErr, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+		})
+	}
+}
+
+func Test_groupMembershipModifyPlanMembersClaimed(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		members     []string
+		expectedErr string
+	}{
+		"new_members": {members: []string{}},
+		// another membership of the same group manages alice
+		"already_member": {members: []string{"alice"}, expectedErr: "alice"},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			fake := newFakeRedshift(t)
+			fake.results = map[string][]string{"JOIN pg_group": test.members}
+			client := redshift.NewClient(fake.connConfig(t))
+			defer client.Close()
+
+			r := &groupMembershipResource{Client: client}
+
+			schemaResp := &fwresource.SchemaResponse{}
+			r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+			usernames, diags := fwtypes.SetValueFrom(ctx, fwtypes.StringType, []string{"Alice", "bob"})
+			if diags.HasError() {
+				t.Fatalf("failed to build usernames: %v", diags)
+			}
+			plan := testState(t, ctx, schemaResp.Schema, &generated.GroupMembershipModel{
+				GroupName: fwtypes.StringValue("devs"),
+				Id:        fwtypes.StringUnknown(),
+				Usernames: usernames,
+			})
+
+			resp := &fwresource.ModifyPlanResponse{
+				Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw.Copy()},
+			}
+			r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+				Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw.Copy()},
+				State:  tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Schema.Type().TerraformType(ctx), nil)},
+			}, resp)
+
+			if test.expectedErr == "" {
+				assert.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+				return
+			}
+
+			if assert.Len(t, resp.Diagnostics.Errors(), 1) {
+				assert.Equal(t, "Users Already In Group", resp.Diagnostics.Errors()[0].Summary())
+				assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "The user(s) Alice already belong to group devs")
+				assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "devs|Alice")
+			}
+		})
+	}
+}
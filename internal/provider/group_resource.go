@@ -7,11 +7,10 @@ import (
 	"terraform-provider-redshift/internal/helpers"
 	"terraform-provider-redshift/internal/redshift"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/tracelog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -20,6 +19,7 @@ var (
 	_ resource.ResourceWithConfigure      = &groupResource{}
 	_ resource.ResourceWithValidateConfig = &groupResource{}
 	_ resource.ResourceWithImportState    = &groupResource{}
+	_ resource.ResourceWithModifyPlan     = &groupResource{}
 )
 
 func NewGroupResource() resource.Resource {
@@ -27,7 +27,8 @@ func NewGroupResource() resource.Resource {
 }
 
 type groupResource struct {
-	ConnCfg *pgx.ConnConfig
+	Client     redshift.Executor
+	NamePolicy namePolicy
 }
 
 func (r *groupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -35,22 +36,28 @@ func (r *groupResource) Metadata(ctx context.Context, req resource.MetadataReque
 }
 
 func (r *groupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = generated.GroupResourceSchema(ctx)
+	resp.Schema = withTimeouts(ctx, generated.GroupResourceSchema(ctx))
 }
 
 func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan generated.GroupModel
+	var planTimeouts timeouts.Value
 
 	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	r.ConnCfg.Tracer = &tracelog.TraceLog{
-		Logger:   helpers.NewLogger(ctx, "group_resource.Create"),
-		LogLevel: tracelog.LogLevelTrace,
+	ctx = helpers.WithSubsystem(ctx, "group_resource.Create")
+
+	timeout, diags := planTimeouts.Create(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
 	var usernames []string
 	if !plan.Usernames.IsUnknown() {
@@ -66,25 +73,15 @@ func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest,
 		Usernames: &usernames,
 	}
 
-	svc, err := redshift.NewGroupService(ctx, r.ConnCfg)
+	svc, err := redshift.NewGroupService(ctx, r.Client)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute NewGroupService",
-			"An unexpected error occurred when calling NewGroupService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Create: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewGroupService", "Create", err)
 		return
 	}
 
 	group, err := svc.CreateGroup(createDDL)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute CreateGroup on service GroupService",
-			"An unexpected error occurred when calling CreateGroup on service GroupService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Create: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "CreateGroup on service GroupService", "Create", err)
 		return
 	}
 
@@ -94,7 +91,7 @@ func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest,
 	// if resp.Diagnostics.HasError() {
 	// 	return
 	// }
-	g, diags := types.SetValueFrom(ctx, types.StringType, *group.Users)
+	g, diags := types.SetValueFrom(ctx, types.StringType, helpers.ReconcileIdentifiers(usernames, *group.Users))
 	diags.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -102,51 +99,61 @@ func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest,
 	plan.Usernames = g
 
 	// Save data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
 }
 
 func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
 	var state generated.GroupModel
+	var stateTimeouts timeouts.Value
 
 	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Read API call logic
-	r.ConnCfg.Tracer = &tracelog.TraceLog{
-		Logger:   helpers.NewLogger(ctx, "group_resource.Read"),
-		LogLevel: tracelog.LogLevelTrace,
+	ctx = helpers.WithSubsystem(ctx, "group_resource.Read")
+
+	timeout, diags := stateTimeouts.Read(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	svc, err := redshift.NewGroupService(ctx, r.ConnCfg)
+	svc, err := redshift.NewGroupService(ctx, r.Client)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute NewGroupService",
-			"An unexpected error occurred when calling NewGroupService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Read: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewGroupService", "Read", err)
 		return
 	}
 
 	group, err := svc.FindGroup(state.Id.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute FindGroup on service GroupService",
-			"An unexpected error occurred when calling FindGroup on service GroupService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Read: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "FindGroup on service GroupService", "Read", err)
 		return
 	}
 
+	// keep the spelling from state, redshift may have folded the case
+	var state_usernames []string
+	if !state.Usernames.IsNull() {
+		resp.Diagnostics.Append(state.Usernames.ElementsAs(ctx, &state_usernames, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// state.Usernames = helpers.SetValueOrNull[string](ctx, types.StringType, *group.Users, &resp.Diagnostics)
 	// if resp.Diagnostics.HasError() {
 	// 	return
 	// }
-	g, diags := types.SetValueFrom(ctx, types.StringType, *group.Users)
+	g, diags := types.SetValueFrom(ctx, types.StringType, helpers.ReconcileIdentifiers(state_usernames, *group.Users))
 	diags.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -156,7 +163,7 @@ func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	state.Name = types.StringValue(group.Name)
 
 	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &state, stateTimeouts)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -164,19 +171,25 @@ func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp
 
 func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan, state generated.GroupModel
+	var planTimeouts timeouts.Value
 
 	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, nil)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Update API call logic
-	r.ConnCfg.Tracer = &tracelog.TraceLog{
-		Logger:   helpers.NewLogger(ctx, "group_resource.Update"),
-		LogLevel: tracelog.LogLevelTrace,
+	ctx = helpers.WithSubsystem(ctx, "group_resource.Update")
+
+	timeout, diags := planTimeouts.Update(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
 	ddl := redshift.AlterGroupDDLParams{
 		Name: state.Name.ValueString(),
@@ -187,8 +200,8 @@ func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest,
 		ddl.RenameTo = &newName
 	}
 
-	if !plan.Usernames.Equal(state.Usernames) {
-		var plan_usernames, state_usernames []string
+	if !plan.Usernames.IsUnknown() && !plan.Usernames.Equal(state.Usernames) {
+		var plan_usernames []string
 
 		diags := plan.Usernames.ElementsAs(ctx, &plan_usernames, false)
 		resp.Diagnostics.Append(diags...)
@@ -196,82 +209,57 @@ func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest,
 			return
 		}
 
-		diags = state.Usernames.ElementsAs(ctx, &state_usernames, false)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-
-		// if plan has more, those are add
-		adds := helpers.MissingFrom(plan_usernames, state_usernames)
-
-		// if state has more, those are drop
-		drops := helpers.MissingFrom(state_usernames, plan_usernames)
-
-		ddl.Add = &adds
-		ddl.Drop = &drops
+		// the service works out what to add and drop against the catalog by
+		// user id, so a renamed user or a change of case is not churned
+		ddl.Usernames = &plan_usernames
 	}
 
-	svc, err := redshift.NewGroupService(ctx, r.ConnCfg)
+	svc, err := redshift.NewGroupService(ctx, r.Client)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute NewGroupService",
-			"An unexpected error occurred when calling NewGroupService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Update: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewGroupService", "Update", err)
 		return
 	}
 
 	err = svc.AlterGroup(ddl)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute AlterGroup on service GroupService",
-			"An unexpected error occurred when calling AlterGroup on service GroupService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Update: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "AlterGroup on service GroupService", "Update", err)
 		return
 	}
 
 	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
 }
 
 func (r *groupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state generated.GroupModel
+	var stateTimeouts timeouts.Value
 
 	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	r.ConnCfg.Tracer = &tracelog.TraceLog{
-		Logger:   helpers.NewLogger(ctx, "group_resource.Delete"),
-		LogLevel: tracelog.LogLevelTrace,
+	ctx = helpers.WithSubsystem(ctx, "group_resource.Delete")
+
+	timeout, diags := stateTimeouts.Delete(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	svc, err := redshift.NewGroupService(ctx, r.ConnCfg)
+	svc, err := redshift.NewGroupService(ctx, r.Client)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute NewGroupService",
-			"An unexpected error occurred when calling NewGroupService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Delete: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewGroupService", "Delete", err)
 		return
 	}
 
 	err = svc.DropGroup(state.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute DropGroup on service GroupService",
-			"An unexpected error occurred when calling DropGroup on service GroupService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Delete: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "DropGroup on service GroupService", "Delete", err)
 		return
 	}
 }
@@ -285,25 +273,93 @@ func (r *groupResource) Configure(ctx context.Context, req resource.ConfigureReq
 		return
 	}
 
-	cfg, ok := req.ProviderData.(*pgx.ConnConfig)
+	data, ok := req.ProviderData.(providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Type",
-			fmt.Sprintf("Expected *pgx.ConnConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.ConnCfg = cfg
+	r.Client = data.Client
+	r.NamePolicy = data.NamePolicy
 }
 
 func (r *groupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
 	var plan generated.GroupModel
 
-	resp.Diagnostics.Append(req.Config.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(getModel(ctx, req.Config, &plan, nil)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 }
+
+func (r *groupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
+	// nothing to check on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan generated.GroupModel
+
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "group_resource.ModifyPlan")
+
+	checkNamePolicy(ctx, r.NamePolicy, plan.Name, &resp.Diagnostics)
+
+	// the cluster cannot be checked before the provider is configured
+	if !clientConfigured(r.Client) {
+		return
+	}
+
+	var state *generated.GroupModel
+	if !req.State.Raw.IsNull() {
+		state = &generated.GroupModel{}
+
+		resp.Diagnostics.Append(getModel(ctx, req.State, state, nil)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !plan.Name.IsUnknown() && (state == nil || !plan.Name.Equal(state.Name)) {
+		checkNameAvailable(ctx, r.Client, redshift.PrincipalGroup, plan.Name.ValueString(), &resp.Diagnostics)
+	}
+
+	plan.Usernames = planCaseSensitiveUsernames(ctx, r.Client, req.Config, &resp.Plan, plan.Usernames, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Usernames.IsNull() || plan.Usernames.IsUnknown() || (state != nil && plan.Usernames.Equal(state.Usernames)) {
+		return
+	}
+
+	var elements []types.String
+	resp.Diagnostics.Append(plan.Usernames.ElementsAs(ctx, &elements, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// users named by another resource are not known until apply
+	var usernames []string
+	for _, element := range elements {
+		if !element.IsUnknown() {
+			usernames = append(usernames, element.ValueString())
+		}
+	}
+
+	checkUsersExist(ctx, r.Client, usernames, &resp.Diagnostics)
+}
@@ -1,13 +1,16 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/redshift"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccGroup_basic(t *testing.T) {
@@ -121,3 +124,102 @@ func TestAccGroup_users(t *testing.T) {
 		},
 	})
 }
+
+func TestAccGroup_renamedUser(t *testing.T) {
+	group1 := "tst_group1" + strings.ToUpper(acctest.RandStringFromCharSet(10, helpers.CharSetAlpha))
+	user1 := "tst-user1" + strings.ToUpper(acctest.RandStringFromCharSet(10, helpers.CharSetAlpha))
+	user2 := "tst-user2" + strings.ToUpper(acctest.RandStringFromCharSet(10, helpers.CharSetAlpha))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_user" "test1" {
+					name = "%s"
+				}
+				resource "redshift_group" "under_test" {
+					name      = "%s"
+					usernames = [ redshift_user.test1.name ]
+				}
+				`, user1, group1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_group.under_test", "usernames.#", "1"),
+					resource.TestCheckResourceAttr("redshift_group.under_test", "usernames.0", user1),
+				),
+			},
+			// the case of a name alone is not a change
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_user" "test1" {
+					name = "%s"
+				}
+				resource "redshift_group" "under_test" {
+					name      = "%s"
+					usernames = [ "%s" ]
+				}
+				`, user1, group1, strings.ToLower(user1)),
+				PlanOnly: true,
+			},
+			// the user stays a member through the rename
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_user" "test1" {
+					name = "%s"
+				}
+				resource "redshift_group" "under_test" {
+					name      = "%s"
+					usernames = [ redshift_user.test1.name ]
+				}
+				`, user2, group1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_group.under_test", "usernames.#", "1"),
+					resource.TestCheckResourceAttr("redshift_group.under_test", "usernames.0", user2),
+				),
+			},
+		},
+	})
+}
+
+func Test_groupCaseSensitiveSessionPooled(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeRedshift(t)
+	client := redshift.NewClient(fake.connConfig(t))
+	defer client.Close()
+
+	ctx := context.Background()
+
+	groups, err := redshift.NewGroupService(ctx, client)
+	if err != nil {
+		t.Fatalf("failed to create group service: %s", err)
+	}
+	if err := groups.DropGroup("Devs"); err != nil {
+		t.Fatalf("failed to drop group: %s", err)
+	}
+
+	catalog, err := redshift.NewCatalogService(ctx, client)
+	if err != nil {
+		t.Fatalf("failed to create catalog service: %s", err)
+	}
+	if _, err := catalog.PrincipalsNamed("Alice"); err != nil {
+		t.Fatalf("failed to find principals: %s", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	assert.Equal(t, 1, fake.connections)
+
+	// the group's case sensitive identifiers end with its transaction, the
+	// user name on the same connection is folded
+	var principals string
+	for _, query := range fake.queries {
+		if strings.Contains(query, "pg_user") {
+			principals = query
+		}
+	}
+	assert.Contains(t, principals, "usename = 'alice'")
+	assert.Contains(t, principals, "groname = 'Alice'")
+}
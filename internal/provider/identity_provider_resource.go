@@ -0,0 +1,324 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/redshift"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &identityProviderResource{}
+	_ resource.ResourceWithConfigure      = &identityProviderResource{}
+	_ resource.ResourceWithValidateConfig = &identityProviderResource{}
+	_ resource.ResourceWithImportState    = &identityProviderResource{}
+	_ resource.ResourceWithModifyPlan     = &identityProviderResource{}
+)
+
+func NewIdentityProviderResource() resource.Resource {
+	return &identityProviderResource{}
+}
+
+type identityProviderResource struct {
+	Client redshift.Executor
+}
+
+func (r *identityProviderResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_identity_provider"
+}
+
+func (r *identityProviderResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = withTimeouts(ctx, generated.IdentityProviderResourceSchema(ctx))
+}
+
+func (r *identityProviderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan generated.IdentityProviderModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "identity_provider_resource.Create")
+
+	timeout, diags := planTimeouts.Create(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	params := identityProviderParameters(ctx, plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createDDL := redshift.CreateIdentityProviderDDLParams{
+		Name:       plan.Name.ValueString(),
+		Type:       plan.Type.ValueString(),
+		Namespace:  plan.Namespace.ValueString(),
+		Parameters: params,
+		Enabled:    plan.Enabled.ValueBool(),
+	}
+
+	svc, err := redshift.NewIdentityProviderService(ctx, r.Client)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewIdentityProviderService", "Create", err)
+		return
+	}
+
+	idp, err := svc.CreateIdentityProvider(createDDL)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "CreateIdentityProvider on service IdentityProviderService", "Create", err)
+		return
+	}
+
+	// Only set those undefaulted computed
+	plan.Id = types.StringValue(idp.Id)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+func (r *identityProviderResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
+	var state generated.IdentityProviderModel
+	var stateTimeouts timeouts.Value
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "identity_provider_resource.Read")
+
+	timeout, diags := stateTimeouts.Read(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	svc, err := redshift.NewIdentityProviderService(ctx, r.Client)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewIdentityProviderService", "Read", err)
+		return
+	}
+
+	idp, err := svc.FindIdentityProvider(state.Id.ValueString())
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "FindIdentityProvider on service IdentityProviderService", "Read", err)
+		return
+	}
+
+	state.Name = types.StringValue(idp.Name)
+	state.Type = types.StringValue(idp.Type)
+	state.Namespace = types.StringValue(idp.Namespace)
+	state.Enabled = types.BoolValue(idp.Enabled)
+
+	// client_secret is write only, it stays whatever was last applied
+	if idp.Params.Issuer != "" {
+		state.Issuer = types.StringValue(idp.Params.Issuer)
+	}
+	if idp.Params.ClientId != "" {
+		state.ClientId = types.StringValue(idp.Params.ClientId)
+	}
+	if len(idp.Params.Audiences) > 0 {
+		audiences, diags := types.SetValueFrom(ctx, types.StringType, idp.Params.Audiences)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.Audiences = audiences
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &state, stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *identityProviderResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state generated.IdentityProviderModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "identity_provider_resource.Update")
+
+	timeout, diags := planTimeouts.Update(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ddl := redshift.AlterIdentityProviderDDLParams{
+		Name: state.Name.ValueString(),
+	}
+
+	if !plan.Namespace.Equal(state.Namespace) {
+		ddl.Namespace = plan.Namespace.ValueStringPointer()
+	}
+
+	// the parameters are replaced as a whole, so any change sends all of them
+	if !plan.Issuer.Equal(state.Issuer) ||
+		!plan.ClientId.Equal(state.ClientId) ||
+		!plan.ClientSecret.Equal(state.ClientSecret) ||
+		!plan.ClientSecretFile.Equal(state.ClientSecretFile) ||
+		!plan.ClientSecretVersion.Equal(state.ClientSecretVersion) ||
+		!plan.Audiences.Equal(state.Audiences) {
+		params := identityProviderParameters(ctx, plan, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		ddl.Parameters = &params
+	}
+
+	if !plan.Enabled.Equal(state.Enabled) {
+		ddl.Enabled = plan.Enabled.ValueBoolPointer()
+	}
+
+	svc, err := redshift.NewIdentityProviderService(ctx, r.Client)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewIdentityProviderService", "Update", err)
+		return
+	}
+
+	err = svc.AlterIdentityProvider(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "AlterIdentityProvider on service IdentityProviderService", "Update", err)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+func (r *identityProviderResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state generated.IdentityProviderModel
+	var stateTimeouts timeouts.Value
+
+	// Read Terraform prior state into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "identity_provider_resource.Delete")
+
+	timeout, diags := stateTimeouts.Delete(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	svc, err := redshift.NewIdentityProviderService(ctx, r.Client)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewIdentityProviderService", "Delete", err)
+		return
+	}
+
+	err = svc.DropIdentityProvider(state.Name.ValueString())
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "DropIdentityProvider on service IdentityProviderService", "Delete", err)
+		return
+	}
+
+	// If the logic reaches here, it implicitly succeeded and will remove
+	// the resource from state if there are no other errors
+}
+
+func (r *identityProviderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *identityProviderResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Type",
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.Client = data.Client
+}
+
+func (r *identityProviderResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var plan generated.IdentityProviderModel
+
+	resp.Diagnostics.Append(getModel(ctx, req.Config, &plan, nil)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func identityProviderParameters(ctx context.Context, model generated.IdentityProviderModel, diags *diag.Diagnostics) redshift.IdentityProviderParameters {
+	var audiences []string
+	diags.Append(model.Audiences.ElementsAs(ctx, &audiences, false)...)
+
+	clientSecret := model.ClientSecret.ValueString()
+	if !model.ClientSecretFile.IsNull() {
+		data, err := os.ReadFile(model.ClientSecretFile.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("client_secret_file"),
+				"Unable to Read Client Secret",
+				"The file named by client_secret_file could not be read.\n\n"+
+					"Unable to read client secret: "+err.Error(),
+			)
+		}
+
+		// as written by an editor or echo, the secret has no line break
+		clientSecret = strings.TrimRight(string(data), "\r\n")
+	}
+
+	return redshift.IdentityProviderParameters{
+		Issuer:       model.Issuer.ValueString(),
+		ClientId:     model.ClientId.ValueString(),
+		ClientSecret: clientSecret,
+		Audiences:    audiences,
+	}
+}
+
+func (r *identityProviderResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// the plan needs no change, only to wait for the provider configuration
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+}
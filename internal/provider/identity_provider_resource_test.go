@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/helpers"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	fwtypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccIdentityProvider_basic(t *testing.T) {
+	testAccRequiresRedshift(t)
+
+	idp := "tst_idp" + strings.ToLower(acctest.RandStringFromCharSet(10, helpers.CharSetAlpha))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_identity_provider" "under_test" {
+					name          = "%s"
+					namespace     = "aad"
+					issuer        = "https://sts.windows.net/00000000-0000-0000-0000-000000000000/"
+					client_id     = "11111111-1111-1111-1111-111111111111"
+					client_secret = "s3cr3t"
+					audiences     = ["api://11111111-1111-1111-1111-111111111111"]
+				}
+				`, idp),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("redshift_identity_provider.under_test", "id"),
+					resource.TestCheckResourceAttr("redshift_identity_provider.under_test", "name", idp),
+					resource.TestCheckResourceAttr("redshift_identity_provider.under_test", "type", "azure"),
+					resource.TestCheckResourceAttr("redshift_identity_provider.under_test", "enabled", "true"),
+				),
+			},
+			{
+				ResourceName:            "redshift_identity_provider.under_test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"client_secret"},
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_identity_provider" "under_test" {
+					name          = "%s"
+					namespace     = "aad2"
+					issuer        = "https://sts.windows.net/00000000-0000-0000-0000-000000000000/"
+					client_id     = "11111111-1111-1111-1111-111111111111"
+					client_secret = "s3cr3t2"
+					audiences     = ["api://11111111-1111-1111-1111-111111111111"]
+					enabled       = false
+				}
+				`, idp),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_identity_provider.under_test", "namespace", "aad2"),
+					resource.TestCheckResourceAttr("redshift_identity_provider.under_test", "enabled", "false"),
+				),
+			},
+		},
+	})
+}
+
+func Test_identityProviderParameters_clientSecret(t *testing.T) {
+	t.Parallel()
+
+	secretFile := filepath.Join(t.TempDir(), "client_secret")
+	if err := os.WriteFile(secretFile, []byte("fr0m-f1le\n"), 0o600); err != nil {
+		t.Fatalf("failed to write secret: %s", err)
+	}
+
+	tests := map[string]struct {
+		model       generated.IdentityProviderModel
+		expected    string
+		expectedErr bool
+	}{
+		"client_secret": {
+			model: generated.IdentityProviderModel{
+				ClientSecret:     fwtypes.StringValue("s3cr3t"),
+				ClientSecretFile: fwtypes.StringNull(),
+			},
+			expected: "s3cr3t",
+		},
+		"client_secret_file": {
+			model: generated.IdentityProviderModel{
+				ClientSecret:     fwtypes.StringNull(),
+				ClientSecretFile: fwtypes.StringValue(secretFile),
+			},
+			expected: "fr0m-f1le",
+		},
+		"missing_file": {
+			model: generated.IdentityProviderModel{
+				ClientSecret:     fwtypes.StringNull(),
+				ClientSecretFile: fwtypes.StringValue(filepath.Join(t.TempDir(), "missing")),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			test.model.Audiences = fwtypes.SetValueMust(fwtypes.StringType, []attr.Value{fwtypes.StringValue("api://app")})
+
+			var diags diag.Diagnostics
+			params := identityProviderParameters(context.Background(), test.model, &diags)
+
+			assert.Equal(t, test.expectedErr, diags.HasError(), "%v", diags)
+			if !test.expectedErr {
+				assert.Equal(t, test.expected, params.ClientSecret)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-redshift/internal/generated"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	fwtypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_namePolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	p := &RedshiftProvider{version: "test"}
+	configureResp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: testProviderConfig(t, ctx, &generated.RedshiftModel{
+		Host:               fwtypes.StringValue("cluster.example.com"),
+		Port:               fwtypes.Int64Value(5439),
+		Username:           fwtypes.StringValue("admin"),
+		Password:           fwtypes.StringValue("Secret123"),
+		Dbname:             fwtypes.StringValue("dev"),
+		NamePrefixRequired: fwtypes.StringValue("tf_"),
+		IdentifierCase:     fwtypes.StringValue("lower"),
+	})}, configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("failed to configure: %v", configureResp.Diagnostics)
+	}
+
+	tests := map[string]struct {
+		name        string
+		expectedErr bool
+	}{
+		"follows_policy":  {name: "tf_bob"},
+		"missing_prefix":  {name: "bob", expectedErr: true},
+		"upper_case":      {name: "tf_Bob", expectedErr: true},
+		"unknown_at_plan": {name: ""},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// not connected to any cluster, the policy needs none
+			r := NewUserResource().(*userResource)
+			r.Configure(ctx, resource.ConfigureRequest{ProviderData: configureResp.ResourceData}, &resource.ConfigureResponse{})
+
+			schemaResp := &resource.SchemaResponse{}
+			r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+			model := &generated.UserModel{Name: fwtypes.StringValue(test.name)}
+			if test.name == "" {
+				model.Name = fwtypes.StringUnknown()
+			}
+			state := testState(t, ctx, schemaResp.Schema, model)
+
+			resp := &resource.ModifyPlanResponse{}
+			r.ModifyPlan(ctx, resource.ModifyPlanRequest{
+				Plan:  tfsdk.Plan{Schema: state.Schema, Raw: state.Raw},
+				State: tfsdk.State{Schema: state.Schema, Raw: state.Raw.Copy()},
+			}, resp)
+
+			assert.Equal(t, test.expectedErr, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+		})
+	}
+}
+
+func Test_namePolicy_unknown(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	configureResp := &provider.ConfigureResponse{}
+	(&RedshiftProvider{version: "test"}).Configure(ctx, provider.ConfigureRequest{Config: testProviderConfig(t, ctx, &generated.RedshiftModel{
+		Host:               fwtypes.StringValue("cluster.example.com"),
+		NamePrefixRequired: fwtypes.StringUnknown(),
+		IdentifierCase:     fwtypes.StringValue("lower"),
+	})}, configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("failed to configure: %v", configureResp.Diagnostics)
+	}
+
+	r := NewGroupResource().(*groupResource)
+	r.Configure(ctx, resource.ConfigureRequest{ProviderData: configureResp.ResourceData}, &resource.ConfigureResponse{})
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	state := testState(t, ctx, schemaResp.Schema, &generated.GroupModel{Name: fwtypes.StringValue("Devs"), Usernames: fwtypes.SetNull(fwtypes.StringType)})
+
+	// the name is not checked, but not silently
+	resp := &resource.ModifyPlanResponse{}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{
+		Plan:  tfsdk.Plan{Schema: state.Schema, Raw: state.Raw},
+		State: tfsdk.State{Schema: state.Schema, Raw: state.Raw.Copy()},
+	}, resp)
+
+	assert.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	if assert.Len(t, resp.Diagnostics.Warnings(), 1) {
+		assert.Equal(t, "Naming Policy Not Checked", resp.Diagnostics.Warnings()[0].Summary())
+	}
+}
@@ -0,0 +1,329 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/redshift"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &ownerResource{}
+	_ resource.ResourceWithConfigure      = &ownerResource{}
+	_ resource.ResourceWithValidateConfig = &ownerResource{}
+	_ resource.ResourceWithImportState    = &ownerResource{}
+	_ resource.ResourceWithModifyPlan     = &ownerResource{}
+)
+
+func NewOwnerResource() resource.Resource {
+	return &ownerResource{}
+}
+
+type ownerResource struct {
+	Client redshift.Executor
+}
+
+func (r *ownerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_owner"
+}
+
+func (r *ownerResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = withTimeouts(ctx, generated.OwnerResourceSchema(ctx))
+}
+
+func (r *ownerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan generated.OwnerModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "owner_resource.Create")
+
+	timeout, diags := planTimeouts.Create(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ddl := redshift.OwnerDDLParams{
+		ObjectType:     plan.ObjectType.ValueString(),
+		SchemaName:     plan.SchemaName.ValueString(),
+		ObjectName:     plan.ObjectName.ValueString(),
+		Arguments:      plan.Arguments.ValueString(),
+		Owner:          plan.Owner.ValueString(),
+		IncludeObjects: plan.IncludeObjects.ValueBool(),
+	}
+
+	svc, err := redshift.NewOwnerService(ctx, r.Client.ForDatabase(plan.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("object_name"), "NewOwnerService", "Create", err)
+		return
+	}
+
+	_, err = svc.AlterOwner(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("object_name"), "AlterOwner on service OwnerService", "Create", err)
+		return
+	}
+
+	// Only set those undefaulted computed
+	plan.Id = types.StringValue(helpers.JoinId(ddl.ObjectType, ddl.SchemaName, ddl.ObjectName, ddl.Arguments))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+func (r *ownerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
+	var state generated.OwnerModel
+	var stateTimeouts timeouts.Value
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "owner_resource.Read")
+
+	timeout, diags := stateTimeouts.Read(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// id is object_type|schema_name|object_name|arguments
+	parts, err := helpers.SplitId(state.Id.ValueString(), 4)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("id"),
+			"Invalid Identifier",
+			"Expected an identifier of the form object_type|schema_name|object_name|arguments.\n\n"+err.Error(),
+		)
+		return
+	}
+
+	ddl := redshift.OwnerDDLParams{
+		ObjectType:     parts[0],
+		SchemaName:     parts[1],
+		ObjectName:     parts[2],
+		Arguments:      parts[3],
+		IncludeObjects: state.IncludeObjects.ValueBool(),
+	}
+
+	svc, err := redshift.NewOwnerService(ctx, r.Client.ForDatabase(state.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("object_name"), "NewOwnerService", "Read", err)
+		return
+	}
+
+	owner, err := svc.FindOwner(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("object_name"), "FindOwner on service OwnerService", "Read", err)
+		return
+	}
+
+	state.ObjectType = types.StringValue(ddl.ObjectType)
+	state.ObjectName = types.StringValue(owner.Name)
+	// keep the configured spelling of an owner redshift folded
+	state.Owner = types.StringValue(helpers.ReconcileIdentifiers([]string{state.Owner.ValueString()}, []string{owner.Owner})[0])
+
+	if ddl.SchemaName != "" {
+		state.SchemaName = types.StringValue(ddl.SchemaName)
+	}
+	if ddl.Arguments != "" || ddl.ObjectType == "function" || ddl.ObjectType == "procedure" {
+		state.Arguments = types.StringValue(ddl.Arguments)
+	}
+
+	// an object created in the schema by someone else shows up as a change
+	if ddl.IncludeObjects {
+		state.IncludeObjects = types.BoolValue(owner.AllObjectsOwned)
+	} else if state.IncludeObjects.IsNull() {
+		state.IncludeObjects = types.BoolValue(false)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &state, stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *ownerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan generated.OwnerModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "owner_resource.Update")
+
+	timeout, diags := planTimeouts.Update(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// the owner is always reapplied, so objects of the schema are picked up as well
+	ddl := redshift.OwnerDDLParams{
+		ObjectType:     plan.ObjectType.ValueString(),
+		SchemaName:     plan.SchemaName.ValueString(),
+		ObjectName:     plan.ObjectName.ValueString(),
+		Arguments:      plan.Arguments.ValueString(),
+		Owner:          plan.Owner.ValueString(),
+		IncludeObjects: plan.IncludeObjects.ValueBool(),
+	}
+
+	svc, err := redshift.NewOwnerService(ctx, r.Client.ForDatabase(plan.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("object_name"), "NewOwnerService", "Update", err)
+		return
+	}
+
+	_, err = svc.AlterOwner(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("object_name"), "AlterOwner on service OwnerService", "Update", err)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+// Ownership cannot be removed from an object, deleting only stops managing it
+// and warns that the object keeps the owner last applied.
+func (r *ownerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state generated.OwnerModel
+
+	// Read Terraform prior state into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.AddWarning(
+		"Owner Left Unchanged",
+		fmt.Sprintf("Every redshift object has an owner, so the %s %s is still owned by %s. ",
+			state.ObjectType.ValueString(), ownerObjectName(state), state.Owner.ValueString())+
+			"Removing redshift_owner only stops managing it; transfer it to another owner with ALTER ... OWNER TO if needed.",
+	)
+}
+
+// ownerObjectName names the object of a redshift_owner for messages.
+func ownerObjectName(model generated.OwnerModel) string {
+	if model.SchemaName.IsNull() {
+		return model.ObjectName.ValueString()
+	}
+
+	return model.SchemaName.ValueString() + "." + model.ObjectName.ValueString()
+}
+
+func (r *ownerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateInDatabase(ctx, req, resp)
+}
+
+func (r *ownerResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Type",
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.Client = data.Client
+}
+
+func (r *ownerResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var plan generated.OwnerModel
+
+	resp.Diagnostics.Append(getModel(ctx, req.Config, &plan, nil)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.ObjectType.IsNull() || plan.ObjectType.IsUnknown() {
+		return
+	}
+
+	objectType := plan.ObjectType.ValueString()
+
+	switch objectType {
+	case "schema", "database":
+		if !plan.SchemaName.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("schema_name"),
+				"Invalid Attribute Combination",
+				fmt.Sprintf("schema_name must be omitted when object_type is %s.", objectType),
+			)
+		}
+	default:
+		if plan.SchemaName.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("schema_name"),
+				"Missing Attribute Configuration",
+				fmt.Sprintf("schema_name must be set when object_type is %s.", objectType),
+			)
+		}
+	}
+
+	if objectType == "function" || objectType == "procedure" {
+		if plan.Arguments.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("arguments"),
+				"Missing Attribute Configuration",
+				fmt.Sprintf("arguments must be set when object_type is %s, use an empty string for no arguments.", objectType),
+			)
+		}
+	} else if !plan.Arguments.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("arguments"),
+			"Invalid Attribute Combination",
+			fmt.Sprintf("arguments must be omitted when object_type is %s.", objectType),
+		)
+	}
+
+	if objectType != "schema" && plan.IncludeObjects.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("include_objects"),
+			"Invalid Attribute Combination",
+			"include_objects can only be set when object_type is schema.",
+		)
+	}
+}
+
+func (r *ownerResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// the plan needs no change, only to wait for the provider configuration
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+}
@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/helpers"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	fwtypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccOwner_table(t *testing.T) {
+	testAccRequiresRedshift(t)
+
+	suffix := strings.ToLower(acctest.RandStringFromCharSet(10, helpers.CharSetAlpha))
+	table := "tst_owner_table" + suffix
+	user1 := "tst_user1" + suffix
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, fmt.Sprintf("CREATE TABLE public.%s (id integer)", table))
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			testAccExec(t, fmt.Sprintf("DROP TABLE public.%s", table))
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_user" "test1" {
+					name = "%s"
+				}
+				resource "redshift_owner" "under_test" {
+					object_type = "table"
+					schema_name = "public"
+					object_name = "%s"
+					owner       = redshift_user.test1.name
+				}
+				`, user1, table),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_owner.under_test", "id", helpers.JoinId("table", "public", table, "")),
+					resource.TestCheckResourceAttr("redshift_owner.under_test", "owner", user1),
+				),
+			},
+			{
+				ResourceName:      "redshift_owner.under_test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// redshift folds the owner, the configured spelling is kept
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_user" "test1" {
+					name = "%s"
+				}
+				resource "redshift_owner" "under_test" {
+					object_type = "table"
+					schema_name = "public"
+					object_name = "%s"
+					owner       = upper(redshift_user.test1.name)
+				}
+				`, user1, table),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_owner.under_test", "owner", strings.ToUpper(user1)),
+				),
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_user" "test1" {
+					name = "%s"
+				}
+				resource "redshift_owner" "under_test" {
+					object_type = "table"
+					schema_name = "public"
+					object_name = "%s"
+					owner       = upper(redshift_user.test1.name)
+				}
+				`, user1, table),
+				PlanOnly: true,
+			},
+			{
+				// hand the table back so the user can be dropped
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_user" "test1" {
+					name = "%s"
+				}
+				resource "redshift_owner" "under_test" {
+					object_type = "table"
+					schema_name = "public"
+					object_name = "%s"
+					owner       = var.username
+				}
+				`, user1, table),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_owner.under_test", "owner", os.Getenv("TF_VAR_username")),
+				),
+			},
+		},
+	})
+}
+
+func Test_ownerDeleteWarns(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := &ownerResource{}
+
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	state := testState(t, ctx, schemaResp.Schema, &generated.OwnerModel{
+		Id:             fwtypes.StringValue(helpers.JoinId("table", "public", "orders", "")),
+		ObjectType:     fwtypes.StringValue("table"),
+		SchemaName:     fwtypes.StringValue("public"),
+		ObjectName:     fwtypes.StringValue("orders"),
+		Arguments:      fwtypes.StringNull(),
+		Database:       fwtypes.StringNull(),
+		IncludeObjects: fwtypes.BoolValue(false),
+		Owner:          fwtypes.StringValue("etl"),
+	})
+
+	resp := &fwresource.DeleteResponse{}
+	r.Delete(ctx, fwresource.DeleteRequest{State: state}, resp)
+
+	assert.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	if assert.Len(t, resp.Diagnostics.Warnings(), 1) {
+		assert.Equal(t, "Owner Left Unchanged", resp.Diagnostics.Warnings()[0].Summary())
+		assert.Contains(t, resp.Diagnostics.Warnings()[0].Detail(), "the table public.orders is still owned by etl")
+	}
+}
@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"terraform-provider-redshift/internal/redshift"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// the kinds of principal whose names collide with a new one of each kind,
+// redshift refuses a role named like a user and the other way around.
+var conflictingPrincipals = map[string][]string{
+	redshift.PrincipalUser:  {redshift.PrincipalUser, redshift.PrincipalRole},
+	redshift.PrincipalRole:  {redshift.PrincipalRole, redshift.PrincipalUser},
+	redshift.PrincipalGroup: {redshift.PrincipalGroup},
+}
+
+// adds a plan-time error when a principal of a conflicting kind already has
+// the name, rather than failing part way through the apply.
+func checkNameAvailable(ctx context.Context, client redshift.Executor, kind string, name string, diags *diag.Diagnostics) {
+	svc, err := redshift.NewCatalogService(ctx, client)
+	if err != nil {
+		addServiceError(diags, path.Root("name"), "NewCatalogService", "ModifyPlan", err)
+		return
+	}
+
+	kinds, err := svc.PrincipalsNamed(name)
+	if err != nil {
+		addServiceError(diags, path.Root("name"), "PrincipalsNamed on service CatalogService", "ModifyPlan", err)
+		return
+	}
+
+	for _, existing := range kinds {
+		if !slices.Contains(conflictingPrincipals[kind], existing) {
+			continue
+		}
+
+		diags.AddAttributeError(
+			path.Root("name"),
+			"Name Already In Use",
+			fmt.Sprintf("A redshift %s named %q already exists, so the %s cannot be given that name. "+
+				"Import it into the state with terraform import, or choose another name.", existing, name, kind),
+		)
+		return
+	}
+}
+
+// adds a plan-time error when name breaks the provider's naming policy, which
+// attribute validators cannot see as it is only known once the provider is
+// configured. A policy not known until apply is only warned about.
+func checkNamePolicy(ctx context.Context, policy namePolicy, name types.String, diags *diag.Diagnostics) {
+	if policy.unknown {
+		diags.AddAttributeWarning(
+			path.Root("name"),
+			"Naming Policy Not Checked",
+			"The provider attributes name_prefix_required or identifier_case are not known until apply, "+
+				"so the name was not checked against the naming policy. Redshift accepts it regardless.",
+		)
+		return
+	}
+
+	if policy.validator == nil {
+		return
+	}
+
+	resp := &validator.StringResponse{}
+	policy.validator.ValidateString(ctx, validator.StringRequest{
+		Path:           path.Root("name"),
+		PathExpression: path.MatchRoot("name"),
+		ConfigValue:    name,
+	}, resp)
+	diags.Append(resp.Diagnostics...)
+}
+
+// adds a plan-time warning for group members which are not users yet, they
+// may still be created earlier in the same apply.
+func checkUsersExist(ctx context.Context, client redshift.Executor, usernames []string, diags *diag.Diagnostics) {
+	if len(usernames) == 0 {
+		return
+	}
+
+	svc, err := redshift.NewCatalogService(ctx, client)
+	if err != nil {
+		addServiceError(diags, path.Root("usernames"), "NewCatalogService", "ModifyPlan", err)
+		return
+	}
+
+	missing, err := svc.MissingUsers(usernames)
+	if err != nil {
+		addServiceError(diags, path.Root("usernames"), "MissingUsers on service CatalogService", "ModifyPlan", err)
+		return
+	}
+
+	if len(missing) == 0 {
+		return
+	}
+
+	diags.AddAttributeWarning(
+		path.Root("usernames"),
+		"Users Not Found",
+		fmt.Sprintf("No redshift user is named %s. Unless they are created earlier in this apply, "+
+			"adding them to the group will fail.", strings.Join(missing, ", ")),
+	)
+}
+
+// returns the usernames to plan. The case insensitive set modifier keeps the
+// prior usernames when the configured ones only differ by case, on a cluster
+// with enable_case_sensitive_identifier on those are other users, so the
+// configured usernames are planned again.
+func planCaseSensitiveUsernames(ctx context.Context, client redshift.Executor, config tfsdk.Config, plan *tfsdk.Plan, planned types.Set, diags *diag.Diagnostics) types.Set {
+	var configured types.Set
+	diags.Append(config.GetAttribute(ctx, path.Root("usernames"), &configured)...)
+	if diags.HasError() {
+		return planned
+	}
+
+	if configured.IsNull() || configured.IsUnknown() || configured.Equal(planned) {
+		return planned
+	}
+
+	svc, err := redshift.NewCatalogService(ctx, client)
+	if err != nil {
+		addServiceError(diags, path.Root("usernames"), "NewCatalogService", "ModifyPlan", err)
+		return planned
+	}
+
+	caseSensitive, err := svc.CaseSensitiveIdentifiers()
+	if err != nil {
+		addServiceError(diags, path.Root("usernames"), "CaseSensitiveIdentifiers on service CatalogService", "ModifyPlan", err)
+		return planned
+	}
+
+	if !caseSensitive {
+		return planned
+	}
+
+	diags.Append(plan.SetAttribute(ctx, path.Root("usernames"), configured)...)
+
+	return configured
+}
+
+// adds a plan-time error when users a membership is to add already belong to
+// the group, another redshift_group_membership or a redshift_group with
+// usernames would then manage them too and remove them from under it.
+func checkMembersUnclaimed(ctx context.Context, client redshift.Executor, groupName string, usernames []string, diags *diag.Diagnostics) {
+	if len(usernames) == 0 {
+		return
+	}
+
+	svc, err := redshift.NewCatalogService(ctx, client)
+	if err != nil {
+		addServiceError(diags, path.Root("usernames"), "NewCatalogService", "ModifyPlan", err)
+		return
+	}
+
+	members, err := svc.MembersOf(groupName, usernames)
+	if err != nil {
+		addServiceError(diags, path.Root("usernames"), "MembersOf on service CatalogService", "ModifyPlan", err)
+		return
+	}
+
+	if len(members) == 0 {
+		return
+	}
+
+	diags.AddAttributeError(
+		path.Root("usernames"),
+		"Users Already In Group",
+		fmt.Sprintf("The user(s) %s already belong to group %s, so another resource may manage their membership. ", strings.Join(members, ", "), groupName)+
+			"Leave them out, or import them into this resource with terraform import and an id of the group and the usernames, "+
+			fmt.Sprintf("such as %s.", membershipId(groupName, members)),
+	)
+}
@@ -6,14 +6,22 @@ import (
 	"runtime/debug"
 	"terraform-provider-redshift/internal/generated"
 	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/redshift"
+	"terraform-provider-redshift/internal/serverless"
+	"terraform-provider-redshift/internal/tunnel"
+	"terraform-provider-redshift/internal/validators"
+	"time"
 
-	"github.com/jackc/pgx/v5"
-
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
-	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
 )
 
 // Ensure RedshiftProvider satisfies various provider interfaces.
@@ -27,6 +35,27 @@ type RedshiftProvider struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+
+	// builds the redshift-serverless client, replaced by a stub in tests
+	newServerlessAPI func(ctx context.Context, region string, endpoint string) (serverless.API, error)
+}
+
+// providerData is the ResourceData of the configured provider, each resource
+// takes what it needs from it in Configure.
+type providerData struct {
+	// unknownConfig while the provider configuration is not fully known
+	Client redshift.Executor
+	// the users, groups and roles are checked against it when they plan
+	NamePolicy namePolicy
+}
+
+// namePolicy is the naming policy of name_prefix_required and
+// identifier_case.
+type namePolicy struct {
+	// nil when there is none
+	validator validator.String
+	// either attribute is not known until apply, so names cannot be checked
+	unknown bool
 }
 
 func (p *RedshiftProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -50,32 +79,38 @@ func (p *RedshiftProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
-	if cfg.ApplicationName.IsNull() {
-		info, _ := debug.ReadBuildInfo()
-
-		ctx = tflog.SetField(ctx, "build_info", info)
-		tflog.Debug(ctx, "fetched buildinfo")
-
-		cfg.ApplicationName = types.StringValue(fmt.Sprintf("terraform-provider-redshift-%s-%s", p.version, info.GoVersion))
+	// needs nothing from the cluster, so it applies even before it is known
+	policy := namePolicy{unknown: cfg.NamePrefixRequired.IsUnknown() || cfg.IdentifierCase.IsUnknown()}
+	if !policy.unknown {
+		policy.validator = validators.NamePolicyValidator(cfg.NamePrefixRequired.ValueString(), cfg.IdentifierCase.ValueString())
 	}
 
-	t := "user={{(StringValue .Username)}} password={{(StringValue .Password)}} host={{(StringValue .Host)}} port={{(Int64Value .Port)}} dbname={{(StringValue .Dbname)}} {{if (StringValue .Sslmode) -}} sslmode={{(StringValue .Sslmode)}} {{end}}application_name={{(StringValue .ApplicationName)}}{{if (Int64Value .Timeout)}} connect_timeout={{(Int64Value .Timeout)}}{{end}}"
-	dsn, err := helpers.Merge(t, cfg)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to create database dsn",
-			"An unexpected error occurred when creating the Redshift jackc/pgx client config. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to parse config to database: "+err.Error(),
-		)
+	// such as the endpoint of a cluster created in the same run
+	if !req.Config.Raw.IsFullyKnown() {
+		tflog.Info(ctx, "Provider configuration is not known yet, deferring the connection")
+		resp.ResourceData = providerData{
+			Client:     unknownConfig{attributes: unknownAttributes(req.Config.Raw)},
+			NamePolicy: policy,
+		}
 		return
 	}
-	ctx = tflog.SetField(ctx, "dsn", dsn)
-	tflog.Debug(ctx, "converted dsn")
 
-	conn_cfg, err := pgx.ParseConfig(dsn)
+	var beforeConnect func(ctx context.Context, connCfg *pgx.ConnConfig) error
+	if cfg.Serverless != nil {
+		beforeConnect = p.configureServerless(ctx, &cfg, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	info, _ := debug.ReadBuildInfo()
+	ctx = tflog.SetField(ctx, "build_info", info)
+	tflog.Debug(ctx, "fetched buildinfo")
+
+	conn_cfg, err := connConfig(&cfg, fmt.Sprintf("terraform-provider-redshift-%s-%s", p.version, info.GoVersion))
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.AddAttributeError(
+			path.Root("dsn"),
 			"Unable to Create Redshift jackc/pgx client config",
 			"An unexpected error occurred when creating the Redshift jackc/pgx client config. "+
 				"If the error is not clear, please contact the provider developers.\n\n"+
@@ -83,28 +118,169 @@ func (p *RedshiftProvider) Configure(ctx context.Context, req provider.Configure
 		)
 		return
 	}
+	ctx = tflog.SetField(ctx, "host", conn_cfg.Host)
+	ctx = tflog.SetField(ctx, "port", conn_cfg.Port)
+	ctx = tflog.SetField(ctx, "database", conn_cfg.Database)
+	ctx = tflog.SetField(ctx, "user", conn_cfg.User)
+	tflog.Debug(ctx, "built connection config")
 
-	conn, err := pgx.ConnectConfig(ctx, conn_cfg)
+	err = configureTLS(conn_cfg, &cfg)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Create Redshift jackc/pgx Client",
-			"An unexpected error occurred when creating the Redshift jackc/pgx client. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to connect to database: "+err.Error(),
+			"Unable to Configure TLS",
+			"The certificates given by ssl_root_cert, ssl_cert and ssl_key could not be loaded. "+
+				"Each may be a file path or inline PEM, and ssl_key must not be encrypted.\n\n"+
+				"Unable to load certificates: "+err.Error(),
 		)
 		return
 	}
-	defer conn.Close(ctx)
 
-	err = conn.Ping(ctx)
+	// set once, every resource shares this config; each operation names
+	// its subsystem on the context it queries with
+	conn_cfg.Tracer = helpers.NewTracer(cfg.LogSql.ValueString())
+
+	if cfg.SshTunnel != nil {
+		tun, err := newTunnel(cfg.SshTunnel)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ssh_tunnel"),
+				"Unable to Configure SSH Tunnel",
+				"The credentials for the bastion or its host key could not be loaded. "+
+					"Check the private key or SSH agent, and the known_hosts file or host key.\n\n"+
+					"Unable to configure tunnel: "+err.Error(),
+			)
+			return
+		}
+
+		conn_cfg.DialFunc = tun.DialFunc
+		conn_cfg.LookupFunc = tunnel.LookupFunc
+	}
+
+	// nothing connects until a resource first needs the cluster, or the
+	// audit table is created
+	client := redshift.NewClient(conn_cfg)
+	if !cfg.MaxRetries.IsNull() {
+		client.MaxRetries = int(cfg.MaxRetries.ValueInt64())
+	}
+	if !cfg.RetryBackoff.IsNull() {
+		client.RetryBackoff = time.Duration(cfg.RetryBackoff.ValueInt64()) * time.Second
+	}
+	client.BeforeConnect = beforeConnect
+	if !cfg.StatementTimeout.IsNull() {
+		client.StatementTimeout = time.Duration(cfg.StatementTimeout.ValueInt64()) * time.Second
+	}
+	client.ReadOnly = cfg.ReadOnly.ValueBool()
+	if !cfg.SqlOutputFile.IsNull() {
+		client.SQLOutput = &redshift.SQLOutput{
+			Path: cfg.SqlOutputFile.ValueString(),
+			Only: cfg.SqlOutputOnly.ValueBool(),
+		}
+	}
+	if cfg.Audit != nil {
+		client.Audit = &redshift.Audit{
+			Schema: cfg.Audit.Schema.ValueString(),
+			Table:  cfg.Audit.Table.ValueString(),
+		}
+		if client.Audit.Schema == "" {
+			client.Audit.Schema = "public"
+		}
+
+		// created once rather than checked by every audited transaction,
+		// there is nothing to record without changes
+		if !client.ReadOnly && (client.SQLOutput == nil || !client.SQLOutput.Only) {
+			auditCtx, cancel := context.WithTimeout(ctx, redshift.DefaultTimeout)
+			defer cancel()
+
+			if err := client.CreateAuditTable(auditCtx); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("audit"),
+					"Unable to Create Audit Table",
+					"The audit table could not be created. Check that the provider's user may create tables "+
+						"in the audit schema, or create the table beforehand.\n\n"+
+						"Unable to create audit table: "+err.Error(),
+				)
+				return
+			}
+		}
+	}
+
+	resp.ResourceData = providerData{
+		Client:     client,
+		NamePolicy: policy,
+	}
+}
+
+// looks up the host and port of the serverless workgroup where they are not
+// configured, and temporary credentials when no password is. Nothing is
+// looked up here: the returned function does so before each connection, the
+// endpoint once and the credentials whenever they are about to expire. It is
+// nil when there is nothing to look up.
+func (p *RedshiftProvider) configureServerless(ctx context.Context, cfg *generated.RedshiftModel, diags *diag.Diagnostics) func(ctx context.Context, connCfg *pgx.ConnConfig) error {
+	if !cfg.Host.IsNull() && !cfg.Port.IsNull() && !cfg.Password.IsNull() {
+		return nil
+	}
+
+	workgroup := cfg.Serverless.WorkgroupName.ValueString()
+
+	// loads the AWS configuration without calling AWS
+	api, err := p.newServerlessAPI(ctx, cfg.Serverless.Region.ValueString(), cfg.Serverless.Endpoint.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to ping datasource",
-			"Unexpected error "+err.Error(),
+		diags.AddAttributeError(
+			path.Root("serverless"),
+			"Unable to Create Redshift Serverless Client",
+			"An unexpected error occurred when creating the redshift-serverless client. "+
+				"Check the AWS credentials of the environment.\n\n"+
+				"Unable to create client: "+err.Error(),
 		)
+		return nil
 	}
 
-	resp.ResourceData = conn_cfg
+	var endpoint *serverless.EndpointSource
+	if cfg.Host.IsNull() || cfg.Port.IsNull() {
+		endpoint = serverless.NewEndpointSource(api, workgroup)
+	}
+	var credentials *serverless.CredentialSource
+	if cfg.Password.IsNull() {
+		credentials = serverless.NewCredentialSource(api, workgroup, cfg.Dbname.ValueString())
+	}
+
+	return func(ctx context.Context, connCfg *pgx.ConnConfig) error {
+		if endpoint != nil {
+			if err := endpoint.BeforeConnect(ctx, connCfg); err != nil {
+				return fmt.Errorf("the endpoint of serverless workgroup %s could not be looked up, check its name and region, "+
+					"and that the AWS credentials may call redshift-serverless:GetWorkgroup: %w", workgroup, err)
+			}
+		}
+
+		if credentials != nil {
+			if err := credentials.BeforeConnect(ctx, connCfg); err != nil {
+				return fmt.Errorf("temporary credentials for serverless workgroup %s could not be fetched, check that the AWS credentials "+
+					"may call redshift-serverless:GetCredentials, or configure a password: %w", workgroup, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// prepares the tunnel through the bastion of the ssh_tunnel block, which is
+// connected on the first dial and stays open for as long as the provider runs.
+func newTunnel(cfg *generated.SshTunnelModel) (*tunnel.Tunnel, error) {
+	port := tunnel.DefaultPort
+	if !cfg.Port.IsNull() {
+		port = int(cfg.Port.ValueInt64())
+	}
+
+	return tunnel.New(tunnel.Config{
+		Host:                 cfg.Host.ValueString(),
+		Port:                 port,
+		User:                 cfg.User.ValueString(),
+		PrivateKey:           cfg.PrivateKey.ValueString(),
+		PrivateKeyPassphrase: cfg.PrivateKeyPassphrase.ValueString(),
+		UseAgent:             cfg.UseAgent.ValueBool(),
+		KnownHostsFile:       cfg.KnownHostsFile.ValueString(),
+		HostKey:              cfg.HostKey.ValueString(),
+	})
 }
 
 func (p *RedshiftProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -112,6 +288,13 @@ func (p *RedshiftProvider) Resources(ctx context.Context) []func() resource.Reso
 		NewUserResource,
 		NewRoleResource,
 		NewGroupResource,
+		NewRlsPolicyResource,
+		NewRlsPolicyAttachmentResource,
+		NewTableRlsResource,
+		NewIdentityProviderResource,
+		NewOwnerResource,
+		NewColumnGrantResource,
+		NewGroupMembershipResource,
 	}
 }
 
@@ -124,7 +307,8 @@ func (p *RedshiftProvider) DataSources(ctx context.Context) []func() datasource.
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &RedshiftProvider{
-			version: version,
+			version:          version,
+			newServerlessAPI: serverless.NewAPI,
 		}
 	}
 }
@@ -132,7 +316,9 @@ func New(version string) func() provider.Provider {
 func (p RedshiftProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
 	tflog.Info(ctx, "Configuring Validators")
 
-	return []provider.ConfigValidator{}
+	return []provider.ConfigValidator{
+		providervalidator.RequiredTogether(path.MatchRoot("ssl_cert"), path.MatchRoot("ssl_key")),
+	}
 }
 
 func (p RedshiftProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
@@ -144,11 +330,69 @@ func (p RedshiftProvider) ValidateConfig(ctx context.Context, req provider.Valid
 		return
 	}
 
-	if data.Host.IsUnknown() {
+	// a serverless workgroup supplies whatever of these is left out, dsn
+	// replaces them all
+	if data.Serverless == nil && data.Dsn.IsNull() {
+		for _, required := range []struct {
+			name  string
+			value attr.Value
+		}{
+			{"host", data.Host},
+			{"port", data.Port},
+			{"username", data.Username},
+			{"password", data.Password},
+			{"dbname", data.Dbname},
+		} {
+			if required.value.IsNull() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(required.name),
+					"Missing Required Attribute",
+					fmt.Sprintf("The attribute %s is required unless the provider connects to a serverless workgroup or dsn is set.", required.name),
+				)
+			}
+		}
+	}
+
+	if data.SshTunnel != nil && data.SshTunnel.PrivateKey.IsNull() && !data.SshTunnel.UseAgent.ValueBool() && !data.SshTunnel.UseAgent.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ssh_tunnel"),
+			"Missing SSH Credentials",
+			"The ssh_tunnel block needs a private_key or use_agent = true to authenticate with the bastion.",
+		)
+	}
+
+	validateRootCert(data, &resp.Diagnostics)
+}
+
+// verify-ca and verify-full need a root certificate to verify the server
+// against, either ssl_root_cert or the system certificate pool.
+func validateRootCert(data generated.RedshiftModel, diags *diag.Diagnostics) {
+	if data.SslRootCert.IsUnknown() {
+		return
+	}
+
+	if !data.SslRootCert.IsNull() {
+		if _, err := rootCAs(data.SslRootCert.ValueString()); err != nil {
+			diags.AddAttributeError(
+				path.Root("ssl_root_cert"),
+				"Invalid Root Certificate",
+				"ssl_root_cert must be a file path or inline PEM holding at least one certificate.\n\n"+
+					"Unable to load root certificate: "+err.Error(),
+			)
+		}
 		return
 	}
 
-	if data.Port.IsUnknown() {
+	sslmode := data.Sslmode.ValueString()
+	if sslmode != "verify-ca" && sslmode != "verify-full" {
 		return
 	}
+
+	if _, err := systemCertPool(); err != nil {
+		diags.AddAttributeError(
+			path.Root("ssl_root_cert"),
+			"Missing Root Certificate",
+			fmt.Sprintf("sslmode %s verifies the server certificate, but no ssl_root_cert is configured and the system certificate pool is unavailable: %s", sslmode, err),
+		)
+	}
 }
@@ -1,16 +1,19 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/localredshift"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/jackc/pgx/v5"
 )
 
 var (
@@ -38,17 +41,47 @@ var (
 		}
 
 		provider "redshift" {
-			host     = var.host
-			port     = var.port
-			username = var.username
-			password = var.password
-			dbname   = var.dbname
-			sslmode  = var.sslmode
-			timeout  = var.timeout
+			host            = var.host
+			port            = var.port
+			username        = var.username
+			password        = var.password
+			dbname          = var.dbname
+			sslmode         = var.sslmode
+			connect_timeout = var.timeout
 		}
 	`
 )
 
+// TestMain starts a local PostgreSQL in place of the redshift cluster when
+// REDSHIFT_LOCAL is set, see package localredshift.
+func TestMain(m *testing.M) {
+	if os.Getenv("TF_ACC") == "" || os.Getenv("REDSHIFT_LOCAL") == "" {
+		os.Exit(m.Run())
+	}
+
+	server, err := localredshift.Start(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start local redshift: %s\n", err)
+		os.Exit(1)
+	}
+
+	for name, value := range server.TerraformVariables() {
+		os.Setenv("TF_VAR_"+name, value)
+	}
+
+	code := m.Run()
+	server.Stop()
+	os.Exit(code)
+}
+
+// testAccRequiresRedshift skips tests of features the local PostgreSQL
+// cannot emulate.
+func testAccRequiresRedshift(t *testing.T) {
+	if os.Getenv("REDSHIFT_LOCAL") != "" {
+		t.Skip("requires a redshift cluster, unset REDSHIFT_LOCAL")
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	// You can add code here to run prior to any test case execution, for example assertions
 	// about the appropriate environment variables being set are common to see in a pre-check
@@ -74,6 +107,36 @@ func testAccPreCheck(t *testing.T) {
 	}
 }
 
+// testAccExec runs sql directly against the acceptance test database, for
+// fixtures such as tables that the provider does not manage.
+func testAccExec(t *testing.T, sql string) {
+	sslmode := os.Getenv("TF_VAR_sslmode")
+	if sslmode == "" {
+		sslmode = "require"
+	}
+
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		os.Getenv("TF_VAR_host"),
+		os.Getenv("TF_VAR_port"),
+		os.Getenv("TF_VAR_username"),
+		os.Getenv("TF_VAR_password"),
+		os.Getenv("TF_VAR_dbname"),
+		sslmode,
+	)
+
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		t.Fatalf("testAccExec: unable to connect: %s", err)
+	}
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, sql)
+	if err != nil {
+		t.Fatalf("testAccExec: failed to execute '%s': %s", sql, err)
+	}
+}
+
 func TestAccProvider_config(t *testing.T) {
 	user1 := "tst-user1" + strings.ToUpper(acctest.RandStringFromCharSet(10, helpers.CharSetAlpha))
 
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"terraform-provider-redshift/internal/redshift"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_readOnlyClient(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeRedshift(t)
+	client := redshift.NewClient(fake.connConfig(t))
+	client.ReadOnly = true
+	defer client.Close()
+
+	ctx := context.Background()
+
+	err := client.InTx(ctx, "Test", func(tx redshift.Querier) error {
+		var setting string
+		return tx.QueryRow(ctx, "SELECT setting FROM pg_settings").Scan(&setting)
+	})
+	if err != nil {
+		t.Fatalf("failed to run transaction: %s", err)
+	}
+
+	err = client.InTx(ctx, "Test", func(tx redshift.Querier) error {
+		_, err := tx.Exec(ctx, `CREATE USER "bob" PASSWORD DISABLE`)
+		return err
+	})
+	assert.True(t, errors.Is(err, redshift.ErrReadOnly), "expected ErrReadOnly, got %v", err)
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	// the refused statement never reaches the cluster
+	assert.Equal(t, []string{
+		"SET default_transaction_read_only TO on",
+		"begin",
+		"SELECT setting FROM pg_settings",
+		"commit",
+		"begin",
+		"rollback",
+	}, fake.queries)
+}
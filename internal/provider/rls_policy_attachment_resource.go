@@ -0,0 +1,276 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/redshift"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &rlsPolicyAttachmentResource{}
+	_ resource.ResourceWithConfigure      = &rlsPolicyAttachmentResource{}
+	_ resource.ResourceWithValidateConfig = &rlsPolicyAttachmentResource{}
+	_ resource.ResourceWithImportState    = &rlsPolicyAttachmentResource{}
+	_ resource.ResourceWithModifyPlan     = &rlsPolicyAttachmentResource{}
+)
+
+func NewRlsPolicyAttachmentResource() resource.Resource {
+	return &rlsPolicyAttachmentResource{}
+}
+
+type rlsPolicyAttachmentResource struct {
+	Client redshift.Executor
+}
+
+func (r *rlsPolicyAttachmentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rls_policy_attachment"
+}
+
+func (r *rlsPolicyAttachmentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = withTimeouts(ctx, generated.RlsPolicyAttachmentResourceSchema(ctx))
+}
+
+func (r *rlsPolicyAttachmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan generated.RlsPolicyAttachmentModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "rls_policy_attachment_resource.Create")
+
+	timeout, diags := planTimeouts.Create(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ddl := redshift.RlsAttachmentDDLParams{
+		PolicyName:  plan.PolicyName.ValueString(),
+		SchemaName:  plan.SchemaName.ValueString(),
+		TableName:   plan.TableName.ValueString(),
+		GranteeType: plan.GranteeType.ValueString(),
+		Grantee:     plan.Grantee.ValueString(),
+	}
+
+	svc, err := redshift.NewRlsService(ctx, r.Client.ForDatabase(plan.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("policy_name"), "NewRlsService", "Create", err)
+		return
+	}
+
+	_, err = svc.AttachRlsPolicy(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("policy_name"), "AttachRlsPolicy on service RlsService", "Create", err)
+		return
+	}
+
+	// Only set those undefaulted computed
+	plan.Id = types.StringValue(helpers.JoinId(ddl.PolicyName, ddl.SchemaName, ddl.TableName, ddl.GranteeType, ddl.Grantee))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+func (r *rlsPolicyAttachmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
+	var state generated.RlsPolicyAttachmentModel
+	var stateTimeouts timeouts.Value
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "rls_policy_attachment_resource.Read")
+
+	timeout, diags := stateTimeouts.Read(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// id is policy_name|schema_name|table_name|grantee_type|grantee
+	parts, err := helpers.SplitId(state.Id.ValueString(), 5)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("id"),
+			"Invalid Identifier",
+			"Expected an identifier of the form policy_name|schema_name|table_name|grantee_type|grantee.\n\n"+err.Error(),
+		)
+		return
+	}
+
+	ddl := redshift.RlsAttachmentDDLParams{
+		PolicyName:  parts[0],
+		SchemaName:  parts[1],
+		TableName:   parts[2],
+		GranteeType: parts[3],
+		Grantee:     parts[4],
+	}
+
+	svc, err := redshift.NewRlsService(ctx, r.Client.ForDatabase(state.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("policy_name"), "NewRlsService", "Read", err)
+		return
+	}
+
+	attachment, err := svc.FindRlsAttachment(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("policy_name"), "FindRlsAttachment on service RlsService", "Read", err)
+		return
+	}
+
+	state.PolicyName = types.StringValue(attachment.PolicyName)
+	state.SchemaName = types.StringValue(attachment.SchemaName)
+	state.TableName = types.StringValue(attachment.TableName)
+	state.GranteeType = types.StringValue(ddl.GranteeType)
+	if ddl.GranteeType == "public" {
+		state.Grantee = types.StringNull()
+	} else {
+		state.Grantee = types.StringValue(attachment.Grantee)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &state, stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Every attribute requires replacement, there is nothing to alter.
+func (r *rlsPolicyAttachmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan generated.RlsPolicyAttachmentModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+func (r *rlsPolicyAttachmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state generated.RlsPolicyAttachmentModel
+	var stateTimeouts timeouts.Value
+
+	// Read Terraform prior state into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "rls_policy_attachment_resource.Delete")
+
+	timeout, diags := stateTimeouts.Delete(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ddl := redshift.RlsAttachmentDDLParams{
+		PolicyName:  state.PolicyName.ValueString(),
+		SchemaName:  state.SchemaName.ValueString(),
+		TableName:   state.TableName.ValueString(),
+		GranteeType: state.GranteeType.ValueString(),
+		Grantee:     state.Grantee.ValueString(),
+	}
+
+	svc, err := redshift.NewRlsService(ctx, r.Client.ForDatabase(state.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("policy_name"), "NewRlsService", "Delete", err)
+		return
+	}
+
+	err = svc.DetachRlsPolicy(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("policy_name"), "DetachRlsPolicy on service RlsService", "Delete", err)
+		return
+	}
+
+	// If the logic reaches here, it implicitly succeeded and will remove
+	// the resource from state if there are no other errors
+}
+
+func (r *rlsPolicyAttachmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateInDatabase(ctx, req, resp)
+}
+
+func (r *rlsPolicyAttachmentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Type",
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.Client = data.Client
+}
+
+func (r *rlsPolicyAttachmentResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var plan generated.RlsPolicyAttachmentModel
+
+	resp.Diagnostics.Append(getModel(ctx, req.Config, &plan, nil)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.GranteeType.IsNull() || plan.GranteeType.IsUnknown() || plan.Grantee.IsUnknown() {
+		return
+	}
+
+	if plan.GranteeType.ValueString() == "public" && !plan.Grantee.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("grantee"),
+			"Invalid Attribute Combination",
+			"grantee must be omitted when grantee_type is public.",
+		)
+	}
+
+	if plan.GranteeType.ValueString() != "public" && plan.Grantee.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("grantee"),
+			"Missing Attribute Configuration",
+			fmt.Sprintf("grantee must be set when grantee_type is %s.", plan.GranteeType.ValueString()),
+		)
+	}
+}
+
+func (r *rlsPolicyAttachmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// the plan needs no change, only to wait for the provider configuration
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+}
@@ -0,0 +1,317 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/redshift"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &rlsPolicyResource{}
+	_ resource.ResourceWithConfigure      = &rlsPolicyResource{}
+	_ resource.ResourceWithValidateConfig = &rlsPolicyResource{}
+	_ resource.ResourceWithImportState    = &rlsPolicyResource{}
+	_ resource.ResourceWithModifyPlan     = &rlsPolicyResource{}
+)
+
+func NewRlsPolicyResource() resource.Resource {
+	return &rlsPolicyResource{}
+}
+
+type rlsPolicyResource struct {
+	Client redshift.Executor
+}
+
+func (r *rlsPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rls_policy"
+}
+
+func (r *rlsPolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = withTimeouts(ctx, generated.RlsPolicyResourceSchema(ctx))
+}
+
+func (r *rlsPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan generated.RlsPolicyModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "rls_policy_resource.Create")
+
+	timeout, diags := planTimeouts.Create(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	columns := map[string]string{}
+	if !plan.Columns.IsNull() && !plan.Columns.IsUnknown() {
+		diags := plan.Columns.ElementsAs(ctx, &columns, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	createDDL := redshift.CreateRlsPolicyDDLParams{
+		Name:    plan.Name.ValueString(),
+		Columns: columns,
+		Alias:   plan.RelationAlias.ValueStringPointer(),
+		Using:   plan.Using.ValueString(),
+	}
+
+	svc, err := redshift.NewRlsService(ctx, r.Client.ForDatabase(plan.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewRlsService", "Create", err)
+		return
+	}
+
+	policy, err := svc.CreateRlsPolicy(createDDL)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "CreateRlsPolicy on service RlsService", "Create", err)
+		return
+	}
+
+	// Only set those undefaulted computed
+	plan.Id = types.StringValue(policy.PolicyName)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+func (r *rlsPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
+	var state generated.RlsPolicyModel
+	var stateTimeouts timeouts.Value
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "rls_policy_resource.Read")
+
+	timeout, diags := stateTimeouts.Read(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	svc, err := redshift.NewRlsService(ctx, r.Client.ForDatabase(state.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewRlsService", "Read", err)
+		return
+	}
+
+	policy, err := svc.FindRlsPolicy(state.Id.ValueString())
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "FindRlsPolicy on service RlsService", "Read", err)
+		return
+	}
+
+	state.Name = types.StringValue(policy.PolicyName)
+
+	// Redshift normalizes the predicate, alias and column types, the state
+	// keeps their configured spelling unless they changed.
+	if state.Using.IsNull() || helpers.NormalizeExpression(state.Using.ValueString()) != helpers.NormalizeExpression(policy.Predicate) {
+		state.Using = types.StringValue(policy.Predicate)
+	}
+
+	if policy.Alias == nil || state.RelationAlias.IsNull() || !strings.EqualFold(state.RelationAlias.ValueString(), *policy.Alias) {
+		state.RelationAlias = types.StringPointerValue(policy.Alias)
+	}
+
+	var columns map[string]string
+	if !state.Columns.IsNull() {
+		resp.Diagnostics.Append(state.Columns.ElementsAs(ctx, &columns, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if !rlsColumnsEqual(columns, policy.Columns) {
+		state.Columns = types.MapNull(types.StringType)
+		if len(policy.Columns) > 0 {
+			columns, diags := types.MapValueFrom(ctx, types.StringType, policy.Columns)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			state.Columns = columns
+		}
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &state, stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *rlsPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state generated.RlsPolicyModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "rls_policy_resource.Update")
+
+	timeout, diags := planTimeouts.Update(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// everything but the predicate requires replacement
+	if !plan.Using.Equal(state.Using) {
+		ddl := redshift.AlterRlsPolicyDDLParams{
+			Name:  state.Name.ValueString(),
+			Using: plan.Using.ValueString(),
+		}
+
+		svc, err := redshift.NewRlsService(ctx, r.Client.ForDatabase(state.Database.ValueString()))
+		if err != nil {
+			addServiceError(&resp.Diagnostics, path.Root("name"), "NewRlsService", "Update", err)
+			return
+		}
+
+		err = svc.AlterRlsPolicy(ddl)
+		if err != nil {
+			addServiceError(&resp.Diagnostics, path.Root("name"), "AlterRlsPolicy on service RlsService", "Update", err)
+			return
+		}
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+func (r *rlsPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state generated.RlsPolicyModel
+	var stateTimeouts timeouts.Value
+
+	// Read Terraform prior state into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "rls_policy_resource.Delete")
+
+	timeout, diags := stateTimeouts.Delete(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	svc, err := redshift.NewRlsService(ctx, r.Client.ForDatabase(state.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewRlsService", "Delete", err)
+		return
+	}
+
+	err = svc.DropRlsPolicy(state.Name.ValueString())
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("name"), "DropRlsPolicy on service RlsService", "Delete", err)
+		return
+	}
+
+	// If the logic reaches here, it implicitly succeeded and will remove
+	// the resource from state if there are no other errors
+}
+
+func (r *rlsPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateInDatabase(ctx, req, resp)
+}
+
+func (r *rlsPolicyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Type",
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.Client = data.Client
+}
+
+func (r *rlsPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var plan generated.RlsPolicyModel
+
+	resp.Diagnostics.Append(getModel(ctx, req.Config, &plan, nil)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.RelationAlias.IsNull() && !plan.RelationAlias.IsUnknown() && plan.Columns.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("relation_alias"),
+			"Missing Attribute Configuration",
+			"relation_alias can only be set together with columns.",
+		)
+	}
+}
+
+func (r *rlsPolicyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// the plan needs no change, only to wait for the provider configuration
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+}
+
+// rlsColumnsEqual is whether the configured columns name the same columns and
+// types as those redshift lists for the policy.
+func rlsColumnsEqual(configured map[string]string, actual map[string]string) bool {
+	if len(configured) != len(actual) {
+		return false
+	}
+
+	for name, typ := range configured {
+		actualType, ok := actual[strings.ToLower(name)]
+		if !ok {
+			actualType, ok = actual[name]
+		}
+		if !ok || helpers.NormalizeType(typ) != helpers.NormalizeType(actualType) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"terraform-provider-redshift/internal/helpers"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccRlsPolicy_basic(t *testing.T) {
+	testAccRequiresRedshift(t)
+
+	policy := "tst_policy" + strings.ToLower(acctest.RandStringFromCharSet(10, helpers.CharSetAlpha))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_rls_policy" "under_test" {
+					name    = "%s"
+					columns = { catgroup = "varchar(10)" }
+					using   = "catgroup = 'Concerts'"
+				}
+				`, policy),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_rls_policy.under_test", "id", policy),
+					resource.TestCheckResourceAttr("redshift_rls_policy.under_test", "name", policy),
+					resource.TestCheckResourceAttr("redshift_rls_policy.under_test", "columns.catgroup", "varchar(10)"),
+				),
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_rls_policy" "under_test" {
+					name    = "%s"
+					columns = { catgroup = "varchar(10)" }
+					using   = "catgroup = 'Shows'"
+				}
+				`, policy),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_rls_policy.under_test", "using", "catgroup = 'Shows'"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRlsPolicyAttachment_role(t *testing.T) {
+	testAccRequiresRedshift(t)
+
+	suffix := strings.ToLower(acctest.RandStringFromCharSet(10, helpers.CharSetAlpha))
+	table := "tst_rls_table" + suffix
+	policy := "tst_policy" + suffix
+	role := "tst_role" + suffix
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccExec(t, fmt.Sprintf("CREATE TABLE public.%s (catgroup varchar(10))", table))
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			testAccExec(t, fmt.Sprintf("DROP TABLE public.%s", table))
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "redshift_role" "test" {
+					name = "%s"
+				}
+				resource "redshift_rls_policy" "test" {
+					name    = "%s"
+					columns = { catgroup = "varchar(10)" }
+					using   = "catgroup = 'Concerts'"
+				}
+				resource "redshift_rls_policy_attachment" "under_test" {
+					policy_name  = redshift_rls_policy.test.name
+					table_name   = "%s"
+					grantee_type = "role"
+					grantee      = redshift_role.test.name
+				}
+				resource "redshift_table_rls" "under_test" {
+					table_name       = "%s"
+					conjunction_type = "OR"
+				}
+				`, role, policy, table, table),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_rls_policy_attachment.under_test", "id", helpers.JoinId(policy, "public", table, "role", role)),
+					resource.TestCheckResourceAttr("redshift_rls_policy_attachment.under_test", "schema_name", "public"),
+					resource.TestCheckResourceAttr("redshift_table_rls.under_test", "enabled", "true"),
+					resource.TestCheckResourceAttr("redshift_table_rls.under_test", "conjunction_type", "OR"),
+				),
+			},
+			{
+				ResourceName:      "redshift_rls_policy_attachment.under_test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "redshift_table_rls.under_test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func Test_rlsColumnsEqual(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		configured map[string]string
+		equal      bool
+	}{
+		"same":         {map[string]string{"catgroup": "character varying(10)"}, true},
+		"type_alias":   {map[string]string{"CatGroup": "VARCHAR(10)"}, true},
+		"other_length": {map[string]string{"catgroup": "varchar(20)"}, false},
+		"other_column": {map[string]string{"catname": "varchar(10)"}, false},
+		"extra_column": {map[string]string{"catgroup": "varchar(10)", "catid": "int"}, false},
+		"none":         {nil, false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.equal, rlsColumnsEqual(test.configured, map[string]string{"catgroup": "character varying(10)"}))
+		})
+	}
+}
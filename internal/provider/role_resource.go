@@ -3,15 +3,15 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 	"terraform-provider-redshift/internal/generated"
 	"terraform-provider-redshift/internal/helpers"
 	"terraform-provider-redshift/internal/redshift"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/tracelog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -20,6 +20,7 @@ var (
 	_ resource.ResourceWithConfigure      = &roleResource{}
 	_ resource.ResourceWithValidateConfig = &roleResource{}
 	_ resource.ResourceWithImportState    = &roleResource{}
+	_ resource.ResourceWithModifyPlan     = &roleResource{}
 )
 
 func NewRoleResource() resource.Resource {
@@ -27,7 +28,8 @@ func NewRoleResource() resource.Resource {
 }
 
 type roleResource struct {
-	ConnCfg *pgx.ConnConfig
+	Client     redshift.Executor
+	NamePolicy namePolicy
 }
 
 func (r *roleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -35,47 +37,43 @@ func (r *roleResource) Metadata(ctx context.Context, req resource.MetadataReques
 }
 
 func (r *roleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = generated.RoleResourceSchema(ctx)
+	resp.Schema = withTimeouts(ctx, generated.RoleResourceSchema(ctx))
 }
 
 func (r *roleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan generated.RoleModel
+	var planTimeouts timeouts.Value
 
 	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	r.ConnCfg.Tracer = &tracelog.TraceLog{
-		Logger:   helpers.NewLogger(ctx, "role_resource.Create"),
-		LogLevel: tracelog.LogLevelTrace,
+	ctx = helpers.WithSubsystem(ctx, "role_resource.Create")
+
+	timeout, diags := planTimeouts.Create(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
 	createDDL := redshift.CreateRoleDDLParams{
 		Name:       plan.Name.ValueString(),
 		ExternalId: plan.ExternalId.ValueStringPointer(),
 	}
 
-	svc, err := redshift.NewRoleService(ctx, r.ConnCfg)
+	svc, err := redshift.NewRoleService(ctx, r.Client)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute NewRoleService",
-			"An unexpected error occurred when calling NewRoleService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Create: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewRoleService", "Create", err)
 		return
 	}
 
 	role, err := svc.CreateRole(createDDL)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute CreateRole on service RoleService",
-			"An unexpected error occurred when calling CreateRole on service RoleService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Create: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "CreateRole on service RoleService", "Create", err)
 		return
 	}
 
@@ -84,42 +82,43 @@ func (r *roleResource) Create(ctx context.Context, req resource.CreateRequest, r
 	plan.ExternalId = types.StringPointerValue(role.ExternalId)
 
 	// Save data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
 }
 
 func (r *roleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
 	var state generated.RoleModel
+	var stateTimeouts timeouts.Value
 
 	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	r.ConnCfg.Tracer = &tracelog.TraceLog{
-		Logger:   helpers.NewLogger(ctx, "role_resource.Read"),
-		LogLevel: tracelog.LogLevelTrace,
+	ctx = helpers.WithSubsystem(ctx, "role_resource.Read")
+
+	timeout, diags := stateTimeouts.Read(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	svc, err := redshift.NewRoleService(ctx, r.ConnCfg)
+	svc, err := redshift.NewRoleService(ctx, r.Client)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute NewRoleService",
-			"An unexpected error occurred when calling NewRoleService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Read: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewRoleService", "Read", err)
 		return
 	}
 
 	role, err := svc.FindRole(state.Id.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute FindRole on service RoleService",
-			"An unexpected error occurred when calling FindRole on service RoleService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Read: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "FindRole on service RoleService", "Read", err)
 		return
 	}
 
@@ -127,7 +126,7 @@ func (r *roleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	state.Name = types.StringValue(role.RoleName)
 
 	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &state, stateTimeouts)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -135,18 +134,24 @@ func (r *roleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 
 func (r *roleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan, state generated.RoleModel
+	var planTimeouts timeouts.Value
 
 	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, nil)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	r.ConnCfg.Tracer = &tracelog.TraceLog{
-		Logger:   helpers.NewLogger(ctx, "role_resource.Update"),
-		LogLevel: tracelog.LogLevelTrace,
+	ctx = helpers.WithSubsystem(ctx, "role_resource.Update")
+
+	timeout, diags := planTimeouts.Update(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
 	ddl := redshift.AlterRoleDDLParams{
 		Name: state.Name.ValueString(),
@@ -161,66 +166,52 @@ func (r *roleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		ddl.ExternalId = plan.ExternalId.ValueStringPointer()
 	}
 
-	svc, err := redshift.NewRoleService(ctx, r.ConnCfg)
+	svc, err := redshift.NewRoleService(ctx, r.Client)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute NewRoleService",
-			"An unexpected error occurred when calling NewRoleService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Update: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewRoleService", "Update", err)
 		return
 	}
 
 	err = svc.AlterRole(ddl)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute AlterRole on service RoleService",
-			"An unexpected error occurred when calling AlterRole on service RoleService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Update: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "AlterRole on service RoleService", "Update", err)
 		return
 	}
 
 	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
 }
 
 func (r *roleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state generated.RoleModel
+	var stateTimeouts timeouts.Value
 
 	// Read Terraform prior state into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	r.ConnCfg.Tracer = &tracelog.TraceLog{
-		Logger:   helpers.NewLogger(ctx, "role_resource.Delete"),
-		LogLevel: tracelog.LogLevelTrace,
+	ctx = helpers.WithSubsystem(ctx, "role_resource.Delete")
+
+	timeout, diags := stateTimeouts.Delete(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	svc, err := redshift.NewRoleService(ctx, r.ConnCfg)
+	svc, err := redshift.NewRoleService(ctx, r.Client)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute NewRoleService",
-			"An unexpected error occurred when calling NewRoleService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Delete: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewRoleService", "Delete", err)
 		return
 	}
 
 	err = svc.DropRole(state.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute DropRole on service RoleService",
-			"An unexpected error occurred when calling DropRole on service RoleService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Delete: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "DropRole on service RoleService", "Delete", err)
 		return
 	}
 
@@ -237,25 +228,74 @@ func (r *roleResource) Configure(ctx context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	cfg, ok := req.ProviderData.(*pgx.ConnConfig)
+	data, ok := req.ProviderData.(providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Type",
-			fmt.Sprintf("Expected *pgx.ConnConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.ConnCfg = cfg
+	r.Client = data.Client
+	r.NamePolicy = data.NamePolicy
 }
 
 func (r *roleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
 	var plan generated.RoleModel
 
-	resp.Diagnostics.Append(req.Config.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(getModel(ctx, req.Config, &plan, nil)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *roleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
+	// nothing to check on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan generated.RoleModel
 
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, nil)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx = helpers.WithSubsystem(ctx, "role_resource.ModifyPlan")
+
+	checkNamePolicy(ctx, r.NamePolicy, plan.Name, &resp.Diagnostics)
+
+	// the cluster cannot be checked before the provider is configured
+	if !clientConfigured(r.Client) {
+		return
+	}
+
+	if plan.Name.IsUnknown() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state generated.RoleModel
+
+		resp.Diagnostics.Append(getModel(ctx, req.State, &state, nil)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// roles are matched without case, only a real rename can collide
+		if strings.EqualFold(plan.Name.ValueString(), state.Name.ValueString()) {
+			return
+		}
+	}
+
+	checkNameAvailable(ctx, r.Client, redshift.PrincipalRole, plan.Name.ValueString(), &resp.Diagnostics)
 }
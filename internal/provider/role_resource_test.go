@@ -2,6 +2,7 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"terraform-provider-redshift/internal/helpers"
 	"testing"
@@ -48,3 +49,31 @@ func TestAccRole_basic(t *testing.T) {
 		},
 	})
 }
+
+func TestAccRole_nameTakenByUser(t *testing.T) {
+	name := "tst-role" + acctest.RandStringFromCharSet(10, helpers.CharSetAlpha)
+	user := fmt.Sprintf(`
+		resource "redshift_user" "existing" {
+			name = "%s"
+		}
+		`, name)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + user,
+			},
+			{
+				Config: providerConfig + user + fmt.Sprintf(`
+				resource "redshift_role" "under_test" {
+					name = "%s"
+				}
+				`, name),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Name Already In Use"),
+			},
+		},
+	})
+}
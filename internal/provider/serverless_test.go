@@ -0,0 +1,203 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/redshift"
+	"terraform-provider-redshift/internal/serverless"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftserverless"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftserverless/types"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	fwtypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubServerless is a workgroup whose endpoint is the fake redshift.
+type stubServerless struct {
+	host string
+	port int32
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *stubServerless) called() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+}
+
+func (s *stubServerless) GetWorkgroup(ctx context.Context, params *redshiftserverless.GetWorkgroupInput, optFns ...func(*redshiftserverless.Options)) (*redshiftserverless.GetWorkgroupOutput, error) {
+	s.called()
+
+	if aws.ToString(params.WorkgroupName) != "analytics" {
+		return nil, errors.New("ResourceNotFoundException: Workgroup not found")
+	}
+
+	return &redshiftserverless.GetWorkgroupOutput{
+		Workgroup: &types.Workgroup{Endpoint: &types.Endpoint{Address: aws.String(s.host), Port: aws.Int32(s.port)}},
+	}, nil
+}
+
+func (s *stubServerless) GetCredentials(ctx context.Context, params *redshiftserverless.GetCredentialsInput, optFns ...func(*redshiftserverless.Options)) (*redshiftserverless.GetCredentialsOutput, error) {
+	s.called()
+
+	return &redshiftserverless.GetCredentialsOutput{
+		DbUser:     aws.String("IAMR:deployer"),
+		DbPassword: aws.String("temporary"),
+		Expiration: aws.Time(time.Now().Add(15 * time.Minute)),
+	}, nil
+}
+
+func testProviderConfig(t *testing.T, ctx context.Context, model *generated.RedshiftModel) tfsdk.Config {
+	t.Helper()
+
+	s := generated.RedshiftProviderSchema(ctx)
+	state := tfsdk.State{
+		Schema: s,
+		Raw:    tftypes.NewValue(s.Type().TerraformType(ctx), nil),
+	}
+
+	diags := state.Set(ctx, model)
+	if diags.HasError() {
+		t.Fatalf("failed to set config: %v", diags)
+	}
+
+	return tfsdk.Config{Schema: s, Raw: state.Raw}
+}
+
+func Test_Configure_serverless(t *testing.T) {
+	t.Parallel()
+
+	host, port, err := net.SplitHostPort(newFakeRedshift(t).listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to split address: %s", err)
+	}
+	portNumber, _ := strconv.Atoi(port)
+
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		model        generated.RedshiftModel
+		expectedUser string
+		expectedErr  string
+	}{
+		"iam_credentials": {
+			model: generated.RedshiftModel{
+				Dbname:     fwtypes.StringValue("dev"),
+				Sslmode:    fwtypes.StringValue("disable"),
+				Serverless: &generated.ServerlessModel{WorkgroupName: fwtypes.StringValue("analytics"), Region: fwtypes.StringValue("eu-west-1")},
+			},
+			expectedUser: "IAMR:deployer",
+		},
+		"password": {
+			model: generated.RedshiftModel{
+				Dbname:     fwtypes.StringValue("dev"),
+				Sslmode:    fwtypes.StringValue("disable"),
+				Username:   fwtypes.StringValue("admin"),
+				Password:   fwtypes.StringValue("Secret123"),
+				Serverless: &generated.ServerlessModel{WorkgroupName: fwtypes.StringValue("analytics"), Region: fwtypes.StringValue("eu-west-1")},
+			},
+			expectedUser: "admin",
+		},
+		"unknown_workgroup": {
+			model: generated.RedshiftModel{
+				Dbname:     fwtypes.StringValue("dev"),
+				Sslmode:    fwtypes.StringValue("disable"),
+				Serverless: &generated.ServerlessModel{WorkgroupName: fwtypes.StringValue("missing"), Region: fwtypes.StringValue("eu-west-1")},
+			},
+			expectedErr: "the endpoint of serverless workgroup missing could not be looked up",
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			api := &stubServerless{host: host, port: int32(portNumber)}
+			p := &RedshiftProvider{
+				version: "test",
+				newServerlessAPI: func(ctx context.Context, region string, endpoint string) (serverless.API, error) {
+					return api, nil
+				},
+			}
+
+			resp := &provider.ConfigureResponse{}
+			p.Configure(ctx, provider.ConfigureRequest{Config: testProviderConfig(t, ctx, &test.model)}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+
+			client, ok := resp.ResourceData.(providerData).Client.(*redshift.Client)
+			if !ok {
+				t.Fatalf("expected *redshift.Client, got %T", resp.ResourceData)
+			}
+			defer client.Close()
+
+			// nothing is looked up until a resource connects
+			assert.Equal(t, 0, api.calls)
+
+			err := client.InTx(ctx, "Test", func(tx redshift.Querier) error {
+				_, err := tx.Exec(ctx, "SELECT 1")
+				return err
+			})
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to run transaction: %s", err)
+			}
+
+			connCfg := client.ConnCfg.Copy()
+			if err := client.BeforeConnect(ctx, connCfg); err != nil {
+				t.Fatalf("failed to look up the workgroup: %s", err)
+			}
+			assert.Equal(t, host, connCfg.Host)
+			assert.Equal(t, uint16(portNumber), connCfg.Port)
+			assert.Equal(t, test.expectedUser, connCfg.User)
+		})
+	}
+}
+
+func Test_ValidateConfig_requires_connection_without_serverless(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	p := &RedshiftProvider{}
+
+	resp := &provider.ValidateConfigResponse{}
+	p.ValidateConfig(ctx, provider.ValidateConfigRequest{Config: testProviderConfig(t, ctx, &generated.RedshiftModel{
+		Dbname: fwtypes.StringValue("dev"),
+		Host:   fwtypes.StringValue("cluster.example.com"),
+	})}, resp)
+
+	var missing []string
+	for _, d := range resp.Diagnostics.Errors() {
+		missing = append(missing, d.Detail())
+	}
+	assert.Equal(t, []string{
+		"The attribute port is required unless the provider connects to a serverless workgroup or dsn is set.",
+		"The attribute username is required unless the provider connects to a serverless workgroup or dsn is set.",
+		"The attribute password is required unless the provider connects to a serverless workgroup or dsn is set.",
+	}, missing)
+
+	resp = &provider.ValidateConfigResponse{}
+	p.ValidateConfig(ctx, provider.ValidateConfigRequest{Config: testProviderConfig(t, ctx, &generated.RedshiftModel{
+		Dbname:     fwtypes.StringValue("dev"),
+		Serverless: &generated.ServerlessModel{WorkgroupName: fwtypes.StringValue("analytics"), Region: fwtypes.StringValue("eu-west-1")},
+	})}, resp)
+	assert.False(t, resp.Diagnostics.HasError())
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"terraform-provider-redshift/internal/redshift"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_sqlOutput(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeRedshift(t)
+	output := filepath.Join(t.TempDir(), "plan.sql")
+	client := redshift.NewClient(fake.connConfig(t))
+	client.SQLOutput = &redshift.SQLOutput{Path: output}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	for _, statements := range [][]string{
+		{"SET LOCAL enable_case_sensitive_identifier TO true", "ALTER USER \"bob\"\n\t\tPASSWORD 'Secret123'", "SELECT 1"},
+		// a transaction which changed nothing is not written
+		{"SET LOCAL enable_case_sensitive_identifier TO true", "SELECT 1"},
+	} {
+		err := client.InTx(ctx, "AlterUser", func(tx redshift.Querier) error {
+			for _, statement := range statements {
+				if _, err := tx.Exec(ctx, statement); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to run transaction: %s", err)
+		}
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("failed to read output: %s", err)
+	}
+
+	assert.Regexp(t, regexp.MustCompile(`^-- \S+ AlterUser in dev\n`+
+		`SET LOCAL enable_case_sensitive_identifier TO true;\n`+
+		`ALTER USER "bob" PASSWORD '\*\*\*';\n\n$`), string(data))
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	assert.Contains(t, fake.queries, "ALTER USER \"bob\"\n\t\tPASSWORD 'Secret123'")
+}
+
+func Test_sqlOutputOnly(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeRedshift(t)
+	output := filepath.Join(t.TempDir(), "plan.sql")
+	client := redshift.NewClient(fake.connConfig(t))
+	client.SQLOutput = &redshift.SQLOutput{Path: output, Only: true}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	users, err := redshift.NewUserService(ctx, client)
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	password := "Secret123"
+	_, err = users.CreateUser(redshift.CreateUserDDLParams{
+		Name:            "bob",
+		Password:        &password,
+		SyslogAccess:    "RESTRICTED",
+		ValidUntil:      "infinity",
+		ConnectionLimit: "UNLIMITED",
+	})
+	assert.True(t, errors.Is(err, redshift.ErrSQLOutputOnly), "expected ErrSQLOutputOnly, got %v", err)
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("failed to read output: %s", err)
+	}
+
+	assert.Regexp(t, regexp.MustCompile(`^-- \S+ CreateUser in dev\n`+
+		`CREATE USER "bob" PASSWORD '\*\*\*' NOCREATEDB NOCREATEUSER SYSLOG ACCESS RESTRICTED VALID UNTIL 'infinity' CONNECTION LIMIT UNLIMITED;\n\n$`), string(data))
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	// the statement never reaches the cluster and the transaction is rolled back
+	for _, query := range fake.queries {
+		assert.NotContains(t, query, "CREATE USER")
+	}
+	assert.Equal(t, "rollback", fake.queries[len(fake.queries)-1])
+}
@@ -0,0 +1,277 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/redshift"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &tableRlsResource{}
+	_ resource.ResourceWithConfigure      = &tableRlsResource{}
+	_ resource.ResourceWithValidateConfig = &tableRlsResource{}
+	_ resource.ResourceWithImportState    = &tableRlsResource{}
+	_ resource.ResourceWithModifyPlan     = &tableRlsResource{}
+)
+
+func NewTableRlsResource() resource.Resource {
+	return &tableRlsResource{}
+}
+
+type tableRlsResource struct {
+	Client redshift.Executor
+}
+
+func (r *tableRlsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_table_rls"
+}
+
+func (r *tableRlsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = withTimeouts(ctx, generated.TableRlsResourceSchema(ctx))
+}
+
+func (r *tableRlsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan generated.TableRlsModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "table_rls_resource.Create")
+
+	timeout, diags := planTimeouts.Create(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ddl := redshift.AlterTableRlsDDLParams{
+		SchemaName:      plan.SchemaName.ValueString(),
+		TableName:       plan.TableName.ValueString(),
+		Enabled:         plan.Enabled.ValueBool(),
+		ConjunctionType: plan.ConjunctionType.ValueStringPointer(),
+	}
+
+	svc, err := redshift.NewRlsService(ctx, r.Client.ForDatabase(plan.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "NewRlsService", "Create", err)
+		return
+	}
+
+	_, err = svc.AlterTableRls(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "AlterTableRls on service RlsService", "Create", err)
+		return
+	}
+
+	// Only set those undefaulted computed
+	plan.Id = types.StringValue(helpers.JoinId(ddl.SchemaName, ddl.TableName))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+func (r *tableRlsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
+	var state generated.TableRlsModel
+	var stateTimeouts timeouts.Value
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "table_rls_resource.Read")
+
+	timeout, diags := stateTimeouts.Read(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// id is schema_name|table_name
+	parts, err := helpers.SplitId(state.Id.ValueString(), 2)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("id"),
+			"Invalid Identifier",
+			"Expected an identifier of the form schema_name|table_name.\n\n"+err.Error(),
+		)
+		return
+	}
+
+	svc, err := redshift.NewRlsService(ctx, r.Client.ForDatabase(state.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "NewRlsService", "Read", err)
+		return
+	}
+
+	table, err := svc.FindTableRls(parts[0], parts[1])
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "FindTableRls on service RlsService", "Read", err)
+		return
+	}
+
+	state.SchemaName = types.StringValue(table.SchemaName)
+	state.TableName = types.StringValue(table.TableName)
+	state.Enabled = types.BoolValue(table.Enabled)
+
+	// the conjunction type is only meaningful while row level security is on
+	if table.Enabled && table.ConjunctionType != nil {
+		state.ConjunctionType = types.StringValue(strings.ToUpper(*table.ConjunctionType))
+	} else if state.ConjunctionType.IsNull() {
+		state.ConjunctionType = types.StringValue("AND")
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &state, stateTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *tableRlsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state generated.TableRlsModel
+	var planTimeouts timeouts.Value
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "table_rls_resource.Update")
+
+	timeout, diags := planTimeouts.Update(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ddl := redshift.AlterTableRlsDDLParams{
+		SchemaName:      state.SchemaName.ValueString(),
+		TableName:       state.TableName.ValueString(),
+		Enabled:         plan.Enabled.ValueBool(),
+		ConjunctionType: plan.ConjunctionType.ValueStringPointer(),
+	}
+
+	svc, err := redshift.NewRlsService(ctx, r.Client.ForDatabase(state.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "NewRlsService", "Update", err)
+		return
+	}
+
+	_, err = svc.AlterTableRls(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "AlterTableRls on service RlsService", "Update", err)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
+}
+
+func (r *tableRlsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state generated.TableRlsModel
+	var stateTimeouts timeouts.Value
+
+	// Read Terraform prior state into the model
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "table_rls_resource.Delete")
+
+	timeout, diags := stateTimeouts.Delete(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// removing the resource turns row level security off
+	ddl := redshift.AlterTableRlsDDLParams{
+		SchemaName: state.SchemaName.ValueString(),
+		TableName:  state.TableName.ValueString(),
+		Enabled:    false,
+	}
+
+	svc, err := redshift.NewRlsService(ctx, r.Client.ForDatabase(state.Database.ValueString()))
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "NewRlsService", "Delete", err)
+		return
+	}
+
+	_, err = svc.AlterTableRls(ddl)
+	if err != nil {
+		addServiceError(&resp.Diagnostics, path.Root("table_name"), "AlterTableRls on service RlsService", "Delete", err)
+		return
+	}
+
+	// If the logic reaches here, it implicitly succeeded and will remove
+	// the resource from state if there are no other errors
+}
+
+func (r *tableRlsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateInDatabase(ctx, req, resp)
+}
+
+func (r *tableRlsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Type",
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.Client = data.Client
+}
+
+func (r *tableRlsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var plan generated.TableRlsModel
+
+	resp.Diagnostics.Append(getModel(ctx, req.Config, &plan, nil)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *tableRlsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// the plan needs no change, only to wait for the provider configuration
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+}
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// The specification has no way to describe the timeouts block, so the
+// resources add it to their generated schemas here and keep its value beside
+// their generated models, which leave it out.
+
+const timeoutsBlock = "timeouts"
+
+// withTimeouts adds the timeouts block, bounding each operation, to the
+// generated schema of a resource.
+func withTimeouts(ctx context.Context, s schema.Schema) schema.Schema {
+	blocks := make(map[string]schema.Block, len(s.Blocks)+1)
+	for name, block := range s.Blocks {
+		blocks[name] = block
+	}
+	blocks[timeoutsBlock] = timeouts.Block(ctx, timeouts.Opts{
+		Create: true,
+		Read:   true,
+		Update: true,
+		Delete: true,
+	})
+	s.Blocks = blocks
+
+	return s
+}
+
+// modelData is the plan, state or configuration of a resource.
+type modelData interface {
+	Get(ctx context.Context, target interface{}) diag.Diagnostics
+}
+
+// getModel reads data into the generated model, and its timeouts block into
+// t when t is not nil.
+func getModel(ctx context.Context, data modelData, model interface{}, t *timeouts.Value) diag.Diagnostics {
+	var object types.Object
+
+	diags := data.Get(ctx, &object)
+	if diags.HasError() {
+		return diags
+	}
+
+	attrTypes := object.AttributeTypes(ctx)
+	attrs := object.Attributes()
+
+	if t != nil {
+		value, ok := attrs[timeoutsBlock].(timeouts.Value)
+		if !ok {
+			diags.AddError(
+				"Unexpected Timeouts Type",
+				fmt.Sprintf("Expected timeouts.Value, got: %T. Please report this issue to the provider developers.", attrs[timeoutsBlock]),
+			)
+
+			return diags
+		}
+		*t = value
+	}
+
+	delete(attrTypes, timeoutsBlock)
+	delete(attrs, timeoutsBlock)
+
+	withoutTimeouts, d := types.ObjectValue(attrTypes, attrs)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	diags.Append(withoutTimeouts.As(ctx, model, basetypes.ObjectAsOptions{})...)
+
+	return diags
+}
+
+// setModel saves the generated model and the timeouts block t into state,
+// a block never read, as after an import, is saved as left out.
+func setModel(ctx context.Context, state *tfsdk.State, model interface{}, t timeouts.Value) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	objectType, ok := state.Schema.Type().(attr.TypeWithAttributeTypes)
+	if !ok {
+		diags.AddError(
+			"Unexpected Schema Type",
+			fmt.Sprintf("Expected an object type, got: %T. Please report this issue to the provider developers.", state.Schema.Type()),
+		)
+
+		return diags
+	}
+
+	attrTypes := make(map[string]attr.Type, len(objectType.AttributeTypes()))
+	for name, attrType := range objectType.AttributeTypes() {
+		attrTypes[name] = attrType
+	}
+	timeoutsType := attrTypes[timeoutsBlock]
+	delete(attrTypes, timeoutsBlock)
+
+	withoutTimeouts, d := types.ObjectValueFrom(ctx, attrTypes, model)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	var value attr.Value = t
+	if !t.Type(ctx).Equal(timeoutsType) {
+		null, err := timeoutsType.ValueFromTerraform(ctx, tftypes.NewValue(timeoutsType.TerraformType(ctx), nil))
+		if err != nil {
+			diags.AddError("Failed to Leave Out Timeouts", err.Error())
+
+			return diags
+		}
+		value = null
+	}
+
+	attrs := withoutTimeouts.Attributes()
+	attrs[timeoutsBlock] = value
+	attrTypes[timeoutsBlock] = timeoutsType
+
+	object, d := types.ObjectValue(attrTypes, attrs)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	diags.Append(state.Set(ctx, object)...)
+
+	return diags
+}
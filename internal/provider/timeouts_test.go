@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/redshift"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_statementTimeoutSetOnSession(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeRedshift(t)
+	client := redshift.NewClient(fake.connConfig(t))
+	client.StatementTimeout = 30 * time.Second
+	defer client.Close()
+
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		err := client.InTx(ctx, "Test", func(tx redshift.Querier) error {
+			_, err := tx.Exec(ctx, "SELECT 1")
+			return err
+		})
+		if err != nil {
+			t.Fatalf("failed to run transaction: %s", err)
+		}
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	// once per connection, before its first transaction
+	assert.Equal(t, []string{
+		"SET statement_timeout TO 30000",
+		"begin",
+		"SELECT 1",
+		"commit",
+		"begin",
+		"SELECT 1",
+		"commit",
+	}, fake.queries)
+}
+
+func Test_modelWithTimeouts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	generatedSchema := generated.RoleResourceSchema(ctx)
+	assert.NotContains(t, generatedSchema.Blocks, "timeouts", "the generated schema is left as the specification describes it")
+
+	model := &generated.RoleModel{
+		ExternalId: types.StringNull(),
+		Id:         types.StringValue("100"),
+		Name:       types.StringValue("analyst"),
+	}
+
+	tests := map[string]struct {
+		timeouts timeouts.Value
+		expected time.Duration
+	}{
+		"left_out": {
+			expected: redshift.DefaultTimeout,
+		},
+		"configured": {
+			timeouts: timeouts.Value{Object: types.ObjectValueMust(
+				map[string]attr.Type{
+					"create": types.StringType,
+					"read":   types.StringType,
+					"update": types.StringType,
+					"delete": types.StringType,
+				},
+				map[string]attr.Value{
+					"create": types.StringValue("20m"),
+					"read":   types.StringNull(),
+					"update": types.StringNull(),
+					"delete": types.StringNull(),
+				},
+			)},
+			expected: 20 * time.Minute,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			state := testState(t, ctx, withTimeouts(ctx, generatedSchema), model)
+			if diags := setModel(ctx, &state, model, test.timeouts); diags.HasError() {
+				t.Fatalf("failed to set model: %v", diags)
+			}
+
+			var actual generated.RoleModel
+			var actualTimeouts timeouts.Value
+			if diags := getModel(ctx, state, &actual, &actualTimeouts); diags.HasError() {
+				t.Fatalf("failed to get model: %v", diags)
+			}
+
+			assert.Equal(t, *model, actual)
+
+			timeout, diags := actualTimeouts.Create(ctx, redshift.DefaultTimeout)
+			if diags.HasError() {
+				t.Fatalf("failed to read create timeout: %v", diags)
+			}
+			assert.Equal(t, test.expected, timeout)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"terraform-provider-redshift/internal/generated"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// replaced in tests, the pool verify-ca and verify-full fall back to without
+// ssl_root_cert
+var systemCertPool = x509.SystemCertPool
+
+// readPEM returns value itself when it is inline PEM, otherwise the contents
+// of the file it names.
+func readPEM(value string) ([]byte, error) {
+	if bytes.Contains([]byte(value), []byte("-----BEGIN ")) {
+		return []byte(value), nil
+	}
+
+	data, err := os.ReadFile(value)
+	if err != nil {
+		return nil, fmt.Errorf("readPEM: Failed to read %s: %w", value, err)
+	}
+
+	return data, nil
+}
+
+func rootCAs(value string) (*x509.CertPool, error) {
+	data, err := readPEM(value)
+	if err != nil {
+		return nil, fmt.Errorf("rootCAs: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("rootCAs: No PEM encoded certificate found")
+	}
+
+	return pool, nil
+}
+
+func clientCertificate(cert string, key string) (tls.Certificate, error) {
+	certPEM, err := readPEM(cert)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("clientCertificate: %w", err)
+	}
+
+	keyPEM, err := readPEM(key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("clientCertificate: %w", err)
+	}
+
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("clientCertificate: Failed to load key pair: %w", err)
+	}
+
+	return certificate, nil
+}
+
+// tlsConfigs mirrors what pgx derives from sslmode for host: the TLS config
+// of each attempt, nil for plain text. As libpq does, require verifies the
+// chain like verify-ca once a root certificate is given.
+func tlsConfigs(sslmode string, host string, hasRootCert bool) []*tls.Config {
+	if sslmode == "disable" {
+		return []*tls.Config{nil}
+	}
+
+	tlsConfig := &tls.Config{}
+
+	// per RFC 6066 no server name is sent for an IP address
+	if net.ParseIP(host) == nil {
+		tlsConfig.ServerName = host
+	}
+
+	if sslmode == "require" && hasRootCert {
+		sslmode = "verify-ca"
+	}
+
+	switch sslmode {
+	case "allow", "prefer", "require":
+		tlsConfig.InsecureSkipVerify = true
+	case "verify-ca":
+		// the default verification also checks the host name, which
+		// verify-ca leaves out
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyChain(tlsConfig.RootCAs, rawCerts)
+		}
+	}
+
+	switch sslmode {
+	case "allow":
+		return []*tls.Config{nil, tlsConfig}
+	case "prefer":
+		return []*tls.Config{tlsConfig, nil}
+	default:
+		return []*tls.Config{tlsConfig}
+	}
+}
+
+// verifyRequiredChain verifies the certificate chain of a dsn with
+// sslmode=require as verify-ca does, as tlsConfigs does for the sslmode
+// attribute when ssl_root_cert is set. require is the one mode pgx leaves
+// unverified which never falls back to a connection without TLS.
+func verifyRequiredChain(connCfg *pgx.ConnConfig) {
+	configs := []*tls.Config{connCfg.TLSConfig}
+	for _, fallback := range connCfg.Fallbacks {
+		configs = append(configs, fallback.TLSConfig)
+	}
+
+	for _, tlsConfig := range configs {
+		if tlsConfig == nil || !tlsConfig.InsecureSkipVerify || tlsConfig.VerifyPeerCertificate != nil {
+			return
+		}
+	}
+
+	for _, tlsConfig := range configs {
+		// RootCAs is only read when the handshake runs, configureTLS sets it
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyChain(tlsConfig.RootCAs, rawCerts)
+		}
+	}
+}
+
+// verifyChain checks the certificates the server sent against roots, the
+// system pool when roots is nil, without looking at the host name.
+func verifyChain(roots *x509.CertPool, rawCerts [][]byte) error {
+	if len(rawCerts) == 0 {
+		return errors.New("verifyChain: The server sent no certificate")
+	}
+
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("verifyChain: Failed to parse server certificate: %w", err)
+		}
+		certs[i] = cert
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	if _, err := certs[0].Verify(opts); err != nil {
+		return fmt.Errorf("verifyChain: %w", err)
+	}
+
+	return nil
+}
+
+// configureTLS applies the ssl_* attributes to the TLS configs pgx derived
+// from sslmode, the primary one and those of its fallbacks. A nil TLS config
+// is a plain text attempt and stays as it is.
+func configureTLS(connCfg *pgx.ConnConfig, cfg *generated.RedshiftModel) error {
+	var pool *x509.CertPool
+	if !cfg.SslRootCert.IsNull() {
+		var err error
+		pool, err = rootCAs(cfg.SslRootCert.ValueString())
+		if err != nil {
+			return fmt.Errorf("configureTLS: %w", err)
+		}
+	}
+
+	var certificates []tls.Certificate
+	if !cfg.SslCert.IsNull() {
+		certificate, err := clientCertificate(cfg.SslCert.ValueString(), cfg.SslKey.ValueString())
+		if err != nil {
+			return fmt.Errorf("configureTLS: %w", err)
+		}
+		certificates = []tls.Certificate{certificate}
+	}
+
+	configs := []*tls.Config{connCfg.TLSConfig}
+	for _, fallback := range connCfg.Fallbacks {
+		configs = append(configs, fallback.TLSConfig)
+	}
+
+	for _, tlsConfig := range configs {
+		if tlsConfig == nil {
+			continue
+		}
+
+		// verify-ca checks the chain against RootCAs when the handshake
+		// runs, so replacing the pool here takes effect
+		if pool != nil {
+			tlsConfig.RootCAs = pool
+		}
+		if certificates != nil {
+			tlsConfig.Certificates = certificates
+		}
+		if !cfg.SslServerName.IsNull() {
+			tlsConfig.ServerName = cfg.SslServerName.ValueString()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"terraform-provider-redshift/internal/generated"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	fwtypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+// newTestCertificate is signed by parent, or by itself when parent is nil.
+func newTestCertificate(t *testing.T, parent *testCertificate, template *x509.Certificate) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %s", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %s", err)
+	}
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+func Test_configureTLS(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCertificate(t, nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	server := newTestCertificate(t, ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "cluster.example.com"},
+		DNSNames:    []string{"cluster.example.com"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	client := newTestCertificate(t, ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "admin"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, []byte(ca.certPEM), 0o600); err != nil {
+		t.Fatalf("failed to write ca: %s", err)
+	}
+
+	tests := map[string]struct {
+		sslmode     string
+		model       generated.RedshiftModel
+		expectedErr string
+	}{
+		"verify_full_inline": {
+			sslmode: "verify-full",
+			model: generated.RedshiftModel{
+				SslRootCert:   fwtypes.StringValue(ca.certPEM),
+				SslCert:       fwtypes.StringValue(client.certPEM),
+				SslKey:        fwtypes.StringValue(client.keyPEM),
+				SslServerName: fwtypes.StringValue("cluster.example.com"),
+			},
+		},
+		"verify_ca_file": {
+			sslmode: "verify-ca",
+			model: generated.RedshiftModel{
+				SslRootCert: fwtypes.StringValue(caFile),
+				SslCert:     fwtypes.StringValue(client.certPEM),
+				SslKey:      fwtypes.StringValue(client.keyPEM),
+			},
+		},
+		"verify_full_wrong_name": {
+			sslmode: "verify-full",
+			model: generated.RedshiftModel{
+				SslRootCert: fwtypes.StringValue(ca.certPEM),
+				SslCert:     fwtypes.StringValue(client.certPEM),
+				SslKey:      fwtypes.StringValue(client.keyPEM),
+			},
+			expectedErr: "doesn't contain any IP SANs",
+		},
+		"missing_root_file": {
+			sslmode:     "verify-ca",
+			model:       generated.RedshiftModel{SslRootCert: fwtypes.StringValue(filepath.Join(dir, "missing.pem"))},
+			expectedErr: "no such file or directory",
+		},
+		"mismatched_key": {
+			sslmode: "verify-ca",
+			model: generated.RedshiftModel{
+				SslCert: fwtypes.StringValue(client.certPEM),
+				SslKey:  fwtypes.StringValue(server.keyPEM),
+			},
+			expectedErr: "private key does not match public key",
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			connCfg, err := pgx.ParseConfig("host=10.0.0.1 sslmode=" + test.sslmode)
+			if err != nil {
+				t.Fatalf("failed to parse config: %s", err)
+			}
+
+			err = configureTLS(connCfg, &test.model)
+			if err == nil {
+				err = handshake(connCfg.TLSConfig, server, ca)
+			}
+
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_configureTLS_keeps_plain_text_fallback(t *testing.T) {
+	t.Parallel()
+
+	connCfg, err := pgx.ParseConfig("host=cluster.example.com sslmode=prefer")
+	if err != nil {
+		t.Fatalf("failed to parse config: %s", err)
+	}
+
+	err = configureTLS(connCfg, &generated.RedshiftModel{SslServerName: fwtypes.StringValue("redshift.internal")})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "redshift.internal", connCfg.TLSConfig.ServerName)
+	if assert.Len(t, connCfg.Fallbacks, 1) {
+		assert.Nil(t, connCfg.Fallbacks[0].TLSConfig)
+	}
+}
+
+// handshake runs a TLS server over a pipe which requires a client
+// certificate signed by ca.
+func handshake(clientConfig *tls.Config, server *testCertificate, ca *testCertificate) error {
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn := tls.Server(serverConn, &tls.Config{
+			Certificates: []tls.Certificate{{Certificate: [][]byte{server.cert.Raw}, PrivateKey: server.key}},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+		})
+		err := conn.Handshake()
+		if err != nil {
+			serverConn.Close()
+		}
+		serverErr <- err
+	}()
+
+	err := tls.Client(clientConn, clientConfig).Handshake()
+	if err != nil {
+		clientConn.Close()
+		<-serverErr
+		return err
+	}
+
+	return <-serverErr
+}
+
+func Test_ValidateConfig_root_cert(t *testing.T) {
+	ctx := context.Background()
+
+	validate := func(model generated.RedshiftModel) []string {
+		model.Dbname = fwtypes.StringValue("dev")
+		model.Host = fwtypes.StringValue("cluster.example.com")
+		model.Port = fwtypes.Int64Value(5439)
+		model.Username = fwtypes.StringValue("admin")
+		model.Password = fwtypes.StringValue("Secret123")
+
+		resp := &provider.ValidateConfigResponse{}
+		RedshiftProvider{}.ValidateConfig(ctx, provider.ValidateConfigRequest{Config: testProviderConfig(t, ctx, &model)}, resp)
+
+		var summaries []string
+		for _, d := range resp.Diagnostics.Errors() {
+			summaries = append(summaries, d.Summary())
+		}
+		return summaries
+	}
+
+	assert.Empty(t, validate(generated.RedshiftModel{Sslmode: fwtypes.StringValue("verify-full")}))
+	assert.Equal(t, []string{"Invalid Root Certificate"}, validate(generated.RedshiftModel{
+		Sslmode:     fwtypes.StringValue("verify-full"),
+		SslRootCert: fwtypes.StringValue("-----BEGIN CERTIFICATE-----\nbm90IGEgY2VydGlmaWNhdGU=\n-----END CERTIFICATE-----\n"),
+	}))
+
+	// not parallel, as it replaces the system pool
+	systemCertPool = func() (*x509.CertPool, error) { return nil, errors.New("no system roots") }
+	defer func() { systemCertPool = x509.SystemCertPool }()
+
+	assert.Equal(t, []string{"Missing Root Certificate"}, validate(generated.RedshiftModel{Sslmode: fwtypes.StringValue("verify-ca")}))
+	assert.Empty(t, validate(generated.RedshiftModel{Sslmode: fwtypes.StringValue("require")}))
+}
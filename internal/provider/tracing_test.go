@@ -0,0 +1,286 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/helpers"
+	"terraform-provider-redshift/internal/redshift"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/jackc/pgx/v5/tracelog"
+)
+
+// fakeRedshift accepts connections and answers every simple query, a SELECT
+// with a single "off" row and anything else with an empty command. SET and
+// SET LOCAL are kept per connection, the latter until the transaction ends,
+// and current_setting reads them back, or else the cluster's settings. A
+// SELECT containing a key of results answers with its rows instead.
+type fakeRedshift struct {
+	listener net.Listener
+	settings map[string]string
+	results  map[string][]string
+
+	mu          sync.Mutex
+	queries     []string
+	connections int
+}
+
+var (
+	fakeSet            = regexp.MustCompile(`(?i)^SET\s+(LOCAL\s+)?(\w+)\s+TO\s+(\w+)$`)
+	fakeCurrentSetting = regexp.MustCompile(`(?i)^SELECT current_setting\('(\w+)'\)$`)
+)
+
+func newFakeRedshift(t *testing.T) *fakeRedshift {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	f := &fakeRedshift{listener: listener}
+	go f.serve()
+
+	return f
+}
+
+func (f *fakeRedshift) connConfig(t *testing.T) *pgx.ConnConfig {
+	t.Helper()
+
+	cfg, err := pgx.ParseConfig(fmt.Sprintf("postgres://fake@%s/dev?sslmode=disable", f.listener.Addr()))
+	if err != nil {
+		t.Fatalf("failed to parse config: %s", err)
+	}
+	// the fake only speaks the simple query protocol
+	cfg.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+
+	return cfg
+}
+
+func (f *fakeRedshift) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		f.mu.Lock()
+		f.connections++
+		f.mu.Unlock()
+
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedshift) handle(conn net.Conn) {
+	defer conn.Close()
+
+	backend := pgproto3.NewBackend(conn, conn)
+	if _, err := backend.ReceiveStartupMessage(); err != nil {
+		return
+	}
+
+	backend.Send(&pgproto3.AuthenticationOk{})
+	backend.Send(&pgproto3.ParameterStatus{Name: "standard_conforming_strings", Value: "on"})
+	backend.Send(&pgproto3.ParameterStatus{Name: "client_encoding", Value: "UTF8"})
+	backend.Send(&pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 1})
+	backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+	if err := backend.Flush(); err != nil {
+		return
+	}
+
+	session := map[string]string{}
+	local := map[string]string{}
+
+	f.mu.Lock()
+	for name, value := range f.settings {
+		session[name] = value
+	}
+	f.mu.Unlock()
+
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			return
+		}
+
+		switch msg := msg.(type) {
+		case *pgproto3.Query:
+			f.mu.Lock()
+			f.queries = append(f.queries, msg.String)
+			f.mu.Unlock()
+
+			query := strings.TrimSpace(msg.String)
+			if m := fakeSet.FindStringSubmatch(query); m != nil {
+				if m[1] != "" {
+					local[m[2]] = m[3]
+				} else {
+					session[m[2]] = m[3]
+				}
+			}
+			if strings.EqualFold(query, "commit") || strings.EqualFold(query, "rollback") {
+				clear(local)
+			}
+
+			if strings.HasPrefix(strings.ToUpper(query), "SELECT") {
+				values := []string{"off"}
+				if m := fakeCurrentSetting.FindStringSubmatch(query); m != nil {
+					if v, ok := session[m[1]]; ok {
+						values = []string{v}
+					}
+					if v, ok := local[m[1]]; ok {
+						values = []string{v}
+					}
+				}
+				f.mu.Lock()
+				for key, rows := range f.results {
+					if strings.Contains(query, key) {
+						values = rows
+					}
+				}
+				f.mu.Unlock()
+
+				backend.Send(&pgproto3.RowDescription{Fields: []pgproto3.FieldDescription{{Name: []byte("setting"), DataTypeOID: 25, DataTypeSize: -1, TypeModifier: -1}}})
+				for _, value := range values {
+					backend.Send(&pgproto3.DataRow{Values: [][]byte{[]byte(value)}})
+				}
+				backend.Send(&pgproto3.CommandComplete{CommandTag: []byte(fmt.Sprintf("SELECT %d", len(values)))})
+			} else {
+				backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("OK")})
+			}
+			backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+			if err := backend.Flush(); err != nil {
+				return
+			}
+		case *pgproto3.Terminate:
+			return
+		default:
+			return
+		}
+	}
+}
+
+// run with -race, resources share one config and used to set its tracer
+// on every operation.
+func Test_concurrentOperationsLogToOwnSubsystem(t *testing.T) {
+	t.Parallel()
+
+	cfg := newFakeRedshift(t).connConfig(t)
+	cfg.Tracer = &tracelog.TraceLog{
+		Logger:   helpers.ContextLogger{},
+		LogLevel: tracelog.LogLevelTrace,
+	}
+	client := redshift.NewClient(cfg)
+
+	users := &userResource{Client: client}
+	groups := &groupResource{Client: client}
+
+	type operation struct {
+		resource  resource.Resource
+		state     tfsdk.State
+		subsystem string
+		statement string
+	}
+
+	ctx := context.Background()
+
+	var operations []operation
+	for i := 0; i < 10; i++ {
+		user := fmt.Sprintf("user%d", i)
+		operations = append(operations, operation{
+			resource:  users,
+			state:     testState(t, ctx, withTimeouts(ctx, generated.UserResourceSchema(ctx)), &generated.UserModel{Name: types.StringValue(user)}),
+			subsystem: "user_resource.Delete",
+			statement: fmt.Sprintf(`DROP USER "%s"`, user),
+		})
+
+		group := fmt.Sprintf("group%d", i)
+		operations = append(operations, operation{
+			resource:  groups,
+			state:     testState(t, ctx, withTimeouts(ctx, generated.GroupResourceSchema(ctx)), &generated.GroupModel{Name: types.StringValue(group), Usernames: types.SetNull(types.StringType)}),
+			subsystem: "group_resource.Delete",
+			statement: fmt.Sprintf(`DROP GROUP "%s"`, group),
+		})
+	}
+
+	logs := make([]bytes.Buffer, len(operations))
+
+	var wg sync.WaitGroup
+	for i := range operations {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			ctx := tflogtest.RootLogger(context.Background(), &logs[i])
+			resp := &resource.DeleteResponse{}
+			operations[i].resource.Delete(ctx, resource.DeleteRequest{State: operations[i].state}, resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Errorf("%s: unexpected error: %v", operations[i].statement, resp.Diagnostics)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, op := range operations {
+		entries, err := tflogtest.MultilineJSONDecode(&logs[i])
+		if err != nil {
+			t.Fatalf("failed to decode logs: %s", err)
+		}
+
+		found := false
+		for _, entry := range entries {
+			sql, ok := entry["sql"].(string)
+			if !ok {
+				continue
+			}
+
+			if module := entry["@module"]; module != "provider."+op.subsystem {
+				t.Errorf("%s: logged %q to %v", op.statement, sql, module)
+			}
+
+			if sql == op.statement {
+				found = true
+			} else if strings.HasPrefix(sql, "DROP") {
+				t.Errorf("%s: logged another operation's %q", op.statement, sql)
+			}
+		}
+
+		if !found {
+			t.Errorf("%s: statement was not logged", op.statement)
+		}
+	}
+}
+
+func testState(t *testing.T, ctx context.Context, s schema.Schema, model interface{}) tfsdk.State {
+	t.Helper()
+
+	state := tfsdk.State{
+		Schema: s,
+		Raw:    tftypes.NewValue(s.Type().TerraformType(ctx), nil),
+	}
+
+	// the timeouts block left out of the configuration
+	diags := setModel(ctx, &state, model, timeouts.Value{})
+	if diags.HasError() {
+		t.Fatalf("failed to set state: %v", diags)
+	}
+
+	return state
+}
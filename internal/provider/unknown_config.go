@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"terraform-provider-redshift/internal/redshift"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var errUnknownConfig = errors.New("the provider configuration depends on values which are not known until apply")
+
+// unknownConfig stands in for the client while the provider configuration is
+// not fully known. Resources defer their reads and plans where Terraform
+// allows it, see deferral. Otherwise they fail once they need the cluster and
+// the checks at plan time are skipped.
+type unknownConfig struct {
+	// the provider attributes not known yet, such as the endpoint of a
+	// cluster created in the same run
+	attributes []string
+}
+
+var _ redshift.Executor = unknownConfig{}
+
+func (u unknownConfig) InTx(ctx context.Context, name string, fn func(tx redshift.Querier) error) error {
+	return fmt.Errorf("%s: %w", name, unknownConfigError{attributes: u.attributes})
+}
+
+func (u unknownConfig) ForDatabase(database string) redshift.Executor {
+	return u
+}
+
+// unknownConfigError is errUnknownConfig naming the attributes not known.
+type unknownConfigError struct {
+	attributes []string
+}
+
+func (e unknownConfigError) Error() string {
+	return fmt.Sprintf("%s: %s", errUnknownConfig, strings.Join(e.attributes, ", "))
+}
+
+func (e unknownConfigError) Is(target error) bool {
+	return target == errUnknownConfig
+}
+
+// unknownAttributes names the attributes of the provider configuration raw
+// which are not known, those of nested blocks as block.attribute.
+func unknownAttributes(raw tftypes.Value) []string {
+	var attributes []string
+
+	_ = tftypes.Walk(raw, func(p *tftypes.AttributePath, v tftypes.Value) (bool, error) {
+		if v.IsKnown() {
+			return true, nil
+		}
+
+		var name strings.Builder
+		for _, step := range p.Steps() {
+			switch step := step.(type) {
+			case tftypes.AttributeName:
+				if name.Len() > 0 {
+					name.WriteString(".")
+				}
+				name.WriteString(string(step))
+			case tftypes.ElementKeyString:
+				fmt.Fprintf(&name, "[%q]", string(step))
+			case tftypes.ElementKeyInt:
+				fmt.Fprintf(&name, "[%d]", int64(step))
+			}
+		}
+		attributes = append(attributes, name.String())
+
+		// nothing below an unknown value is known either
+		return false, nil
+	})
+
+	sort.Strings(attributes)
+
+	return attributes
+}
+
+// clientConfigured is whether client can reach the cluster, a resource has
+// none before the provider is configured.
+func clientConfigured(client redshift.Executor) bool {
+	if client == nil {
+		return false
+	}
+
+	_, unknown := client.(unknownConfig)
+
+	return !unknown
+}
+
+// deferral is the deferral of a resource whose client is unknownConfig, for
+// Terraform to read or plan it once the provider configuration is known. It
+// is nil when the client is configured, or when Terraform does not allow
+// deferrals and the resource is left to fail.
+func deferral(client redshift.Executor, allowed bool) *resource.Deferred {
+	if !allowed {
+		return nil
+	}
+
+	if _, unknown := client.(unknownConfig); !unknown {
+		return nil
+	}
+
+	return &resource.Deferred{Reason: resource.DeferredReasonProviderConfigUnknown}
+}
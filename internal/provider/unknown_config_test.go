@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-redshift/internal/generated"
+	"terraform-provider-redshift/internal/redshift"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	fwtypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Configure_does_not_connect(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		host     fwtypes.String
+		expected interface{}
+	}{
+		// nothing listens there, configuring must not notice
+		"unreachable_host": {
+			host:     fwtypes.StringValue("127.0.0.1"),
+			expected: &redshift.Client{},
+		},
+		"unknown_host": {
+			host:     fwtypes.StringUnknown(),
+			expected: unknownConfig{},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &provider.ConfigureResponse{}
+			(&RedshiftProvider{version: "test"}).Configure(ctx, provider.ConfigureRequest{Config: testProviderConfig(t, ctx, &generated.RedshiftModel{
+				Host:     test.host,
+				Port:     fwtypes.Int64Value(1),
+				Username: fwtypes.StringValue("admin"),
+				Password: fwtypes.StringValue("Secret123"),
+				Dbname:   fwtypes.StringValue("dev"),
+				Sslmode:  fwtypes.StringValue("disable"),
+			})}, resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			assert.IsType(t, test.expected, resp.ResourceData.(providerData).Client)
+		})
+	}
+}
+
+func Test_unknownConfig(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	config := testProviderConfig(t, ctx, &generated.RedshiftModel{
+		Host:      fwtypes.StringUnknown(),
+		Port:      fwtypes.Int64Value(5439),
+		Password:  fwtypes.StringUnknown(),
+		SshTunnel: &generated.SshTunnelModel{Host: fwtypes.StringUnknown(), User: fwtypes.StringValue("ec2-user")},
+	})
+
+	resp := &provider.ConfigureResponse{}
+	(&RedshiftProvider{version: "test"}).Configure(ctx, provider.ConfigureRequest{Config: config}, resp)
+
+	exec := resp.ResourceData.(providerData).Client.ForDatabase("analytics")
+
+	err := exec.InTx(ctx, "CreateUser", func(tx redshift.Querier) error {
+		t.Fatal("must not run")
+		return nil
+	})
+	assert.ErrorIs(t, err, errUnknownConfig)
+	assert.EqualError(t, err, "CreateUser: the provider configuration depends on values which are not known until apply: host, password, ssh_tunnel.host")
+	assert.False(t, clientConfigured(exec))
+	assert.False(t, clientConfigured(nil))
+	assert.True(t, clientConfigured(&redshift.Client{}))
+}
+
+func Test_unknownConfig_deferral(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	p := &RedshiftProvider{version: "test"}
+	configured := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: testProviderConfig(t, ctx, &generated.RedshiftModel{
+		Host: fwtypes.StringUnknown(),
+	})}, configured)
+
+	for _, newResource := range p.Resources(ctx) {
+		r := newResource()
+
+		metadata := &resource.MetadataResponse{}
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "redshift"}, metadata)
+
+		t.Run(metadata.TypeName, func(t *testing.T) {
+			t.Parallel()
+
+			r.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: configured.ResourceData}, &resource.ConfigureResponse{})
+
+			read := &resource.ReadResponse{}
+			r.Read(ctx, resource.ReadRequest{ClientCapabilities: resource.ReadClientCapabilities{DeferralAllowed: true}}, read)
+			if assert.NotNil(t, read.Deferred) {
+				assert.Equal(t, resource.DeferredReasonProviderConfigUnknown, read.Deferred.Reason)
+			}
+
+			plan := &resource.ModifyPlanResponse{}
+			r.(resource.ResourceWithModifyPlan).ModifyPlan(ctx, resource.ModifyPlanRequest{ClientCapabilities: resource.ModifyPlanClientCapabilities{DeferralAllowed: true}}, plan)
+			if assert.NotNil(t, plan.Deferred) {
+				assert.Equal(t, resource.DeferredReasonProviderConfigUnknown, plan.Deferred.Reason)
+			}
+
+			// clients which cannot defer keep the error
+			assert.Nil(t, deferral(configured.ResourceData.(providerData).Client, false))
+		})
+	}
+
+	assert.Nil(t, deferral(&redshift.Client{}, true))
+}
@@ -3,15 +3,15 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 	"terraform-provider-redshift/internal/generated"
 	"terraform-provider-redshift/internal/helpers"
 	"terraform-provider-redshift/internal/redshift"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/tracelog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -20,6 +20,7 @@ var (
 	_ resource.ResourceWithConfigure      = &userResource{}
 	_ resource.ResourceWithValidateConfig = &userResource{}
 	_ resource.ResourceWithImportState    = &userResource{}
+	_ resource.ResourceWithModifyPlan     = &userResource{}
 )
 
 func NewUserResource() resource.Resource {
@@ -27,7 +28,8 @@ func NewUserResource() resource.Resource {
 }
 
 type userResource struct {
-	ConnCfg *pgx.ConnConfig
+	Client     redshift.Executor
+	NamePolicy namePolicy
 }
 
 func (r *userResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -35,22 +37,28 @@ func (r *userResource) Metadata(ctx context.Context, req resource.MetadataReques
 }
 
 func (r *userResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = generated.UserResourceSchema(ctx)
+	resp.Schema = withTimeouts(ctx, generated.UserResourceSchema(ctx))
 }
 
 func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan generated.UserModel
+	var planTimeouts timeouts.Value
 
 	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	r.ConnCfg.Tracer = &tracelog.TraceLog{
-		Logger:   helpers.NewLogger(ctx, "user_resource.Create"),
-		LogLevel: tracelog.LogLevelTrace,
+	ctx = helpers.WithSubsystem(ctx, "user_resource.Create")
+
+	timeout, diags := planTimeouts.Create(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
 	createDDL := redshift.CreateUserDDLParams{
 		Name:            plan.Name.ValueString(),
@@ -64,25 +72,15 @@ func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, r
 		ExternalId:      plan.ExternalId.ValueStringPointer(),
 	}
 
-	svc, err := redshift.NewUserService(ctx, r.ConnCfg)
+	svc, err := redshift.NewUserService(ctx, r.Client)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute NewUserService",
-			"An unexpected error occurred when calling NewUserService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Create: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewUserService", "Create", err)
 		return
 	}
 
 	user, err := svc.CreateUser(createDDL)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute CreateUser on service UserService",
-			"An unexpected error occurred when calling CreateUser on service UserService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Create: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "CreateUser on service UserService", "Create", err)
 		return
 	}
 
@@ -91,42 +89,43 @@ func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, r
 	plan.ExternalId = types.StringPointerValue(user.ExternalId)
 
 	// Save data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
 }
 
 func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
 	var state generated.UserModel
+	var stateTimeouts timeouts.Value
 
 	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	r.ConnCfg.Tracer = &tracelog.TraceLog{
-		Logger:   helpers.NewLogger(ctx, "user_resource.Read"),
-		LogLevel: tracelog.LogLevelTrace,
+	ctx = helpers.WithSubsystem(ctx, "user_resource.Read")
+
+	timeout, diags := stateTimeouts.Read(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	svc, err := redshift.NewUserService(ctx, r.ConnCfg)
+	svc, err := redshift.NewUserService(ctx, r.Client)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute NewUserService",
-			"An unexpected error occurred when calling NewUserService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Read: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewUserService", "Read", err)
 		return
 	}
 
 	svv_data, err := svc.FindUser(state.Id.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute FindUser on service UserService",
-			"An unexpected error occurred when calling FindUser on service UserService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Read: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "FindUser on service UserService", "Read", err)
 		return
 	}
 
@@ -134,13 +133,14 @@ func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	state.Createdb = types.BoolValue(svv_data.CreateDb)
 	state.Createuser = types.BoolValue(svv_data.CreateUser)
 	state.ExternalId = types.StringPointerValue(svv_data.ExternalId)
-	state.Name = types.StringValue(svv_data.UserName)
+	// keep the configured spelling, redshift may have folded the case
+	state.Name = types.StringValue(helpers.ReconcileIdentifiers([]string{state.Name.ValueString()}, []string{svv_data.UserName})[0])
 	state.SessionTimeout = types.Int64Value(svv_data.SessionTimeout)
 	state.SyslogAccess = types.StringValue(svv_data.SyslogAccess)
 	state.ValidUntil = types.StringValue(svv_data.ValidUntil)
 
 	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &state, stateTimeouts)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -148,18 +148,24 @@ func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp
 
 func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan, state generated.UserModel
+	var planTimeouts timeouts.Value
 
 	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, &planTimeouts)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, nil)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	r.ConnCfg.Tracer = &tracelog.TraceLog{
-		Logger:   helpers.NewLogger(ctx, "user_resource.Update"),
-		LogLevel: tracelog.LogLevelTrace,
+	ctx = helpers.WithSubsystem(ctx, "user_resource.Update")
+
+	timeout, diags := planTimeouts.Update(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
 	alterUserDDL := redshift.AlterUserDDLParams{
 		Name: state.Name.ValueString(),
@@ -215,66 +221,52 @@ func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		alterUserDDL.ExternalId = plan.ExternalId.ValueStringPointer()
 	}
 
-	svc, err := redshift.NewUserService(ctx, r.ConnCfg)
+	svc, err := redshift.NewUserService(ctx, r.Client)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute NewUserService",
-			"An unexpected error occurred when calling NewUserService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Update: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewUserService", "Update", err)
 		return
 	}
 
 	err = svc.AlterUser(alterUserDDL)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute AlterUser on service UserService",
-			"An unexpected error occurred when calling AlterUser on service UserService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Update: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "AlterUser on service UserService", "Update", err)
 		return
 	}
 
 	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setModel(ctx, &resp.State, &plan, planTimeouts)...)
 }
 
 func (r *userResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state generated.UserModel
+	var stateTimeouts timeouts.Value
 
 	// Read Terraform prior state into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(getModel(ctx, req.State, &state, &stateTimeouts)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	r.ConnCfg.Tracer = &tracelog.TraceLog{
-		Logger:   helpers.NewLogger(ctx, "user_resource.Delete"),
-		LogLevel: tracelog.LogLevelTrace,
+	ctx = helpers.WithSubsystem(ctx, "user_resource.Delete")
+
+	timeout, diags := stateTimeouts.Delete(ctx, redshift.DefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	svc, err := redshift.NewUserService(ctx, r.ConnCfg)
+	svc, err := redshift.NewUserService(ctx, r.Client)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute NewUserService",
-			"An unexpected error occurred when calling NewUserService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Delete: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "NewUserService", "Delete", err)
 		return
 	}
 
 	err = svc.DropUser(state.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to execute DropUser on service UserService",
-			"An unexpected error occurred when calling DropUser on service UserService. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Unable to Delete: "+err.Error(),
-		)
+		addServiceError(&resp.Diagnostics, path.Root("name"), "DropUser on service UserService", "Delete", err)
 		return
 	}
 
@@ -291,25 +283,74 @@ func (r *userResource) Configure(ctx context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	cfg, ok := req.ProviderData.(*pgx.ConnConfig)
+	data, ok := req.ProviderData.(providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Type",
-			fmt.Sprintf("Expected *pgx.ConnConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.ConnCfg = cfg
+	r.Client = data.Client
+	r.NamePolicy = data.NamePolicy
 }
 
 func (r *userResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
 	var plan generated.UserModel
 
-	resp.Diagnostics.Append(req.Config.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(getModel(ctx, req.Config, &plan, nil)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 }
+
+func (r *userResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Deferred = deferral(r.Client, req.ClientCapabilities.DeferralAllowed)
+	if resp.Deferred != nil {
+		return
+	}
+
+	// nothing to check on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan generated.UserModel
+
+	resp.Diagnostics.Append(getModel(ctx, req.Plan, &plan, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.WithSubsystem(ctx, "user_resource.ModifyPlan")
+
+	checkNamePolicy(ctx, r.NamePolicy, plan.Name, &resp.Diagnostics)
+
+	// the cluster cannot be checked before the provider is configured
+	if !clientConfigured(r.Client) {
+		return
+	}
+
+	if plan.Name.IsUnknown() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state generated.UserModel
+
+		resp.Diagnostics.Append(getModel(ctx, req.State, &state, nil)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// users are matched without case, only a real rename can collide
+		if strings.EqualFold(plan.Name.ValueString(), state.Name.ValueString()) {
+			return
+		}
+	}
+
+	checkNameAvailable(ctx, r.Client, redshift.PrincipalUser, plan.Name.ValueString(), &resp.Diagnostics)
+}
@@ -0,0 +1,146 @@
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"terraform-provider-redshift/internal/helpers"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// the longest VARCHAR redshift stores, in bytes
+const maxVarcharBytes = 65535
+
+// the size of the object_name column, in bytes
+const maxObjectNameBytes = 1024
+
+type auditObjectKey struct{}
+
+// withAuditObject names the object the transactions run with ctx change, the
+// services name it for the audit records of their statements.
+func withAuditObject(ctx context.Context, object string) context.Context {
+	return context.WithValue(ctx, auditObjectKey{}, object)
+}
+
+// auditName joins the parts of a qualified name, such as schema and table.
+func auditName(parts ...string) string {
+	return strings.Join(parts, ".")
+}
+
+// Audit is a table every statement which changes redshift is recorded in,
+// in the transaction which runs it, so a change is never without its record.
+// The provider creates the table in its database when configured, and the
+// client in any other database before its first transaction there.
+type Audit struct {
+	// standard identifiers, folded to lower case as redshift would without
+	// quotes, so that sessions with case sensitive identifiers find the
+	// same table
+	Schema string
+	Table  string
+
+	mu sync.Mutex
+	// the databases the table is known to exist in
+	created map[string]bool
+}
+
+func (a *Audit) table() string {
+	return pgx.Identifier{strings.ToLower(a.Schema), strings.ToLower(a.Table)}.Sanitize()
+}
+
+// create creates the table in the database of pool if it is missing, once
+// for each database.
+func (a *Audit) create(ctx context.Context, database string, pool *pgxpool.Pool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.created[database] {
+		return nil
+	}
+
+	create := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			executed_at   TIMESTAMPTZ    NOT NULL,
+			executed_by   VARCHAR(128)   NOT NULL,
+			operation     VARCHAR(32),
+			resource_type VARCHAR(128),
+			object_name   VARCHAR(1024),
+			service_call  VARCHAR(128)   NOT NULL,
+			statement     VARCHAR(65535) NOT NULL
+		)
+	`, a.table())
+	_, err := pool.Exec(ctx, create)
+	if err != nil {
+		return fmt.Errorf("create: Failed to create audit table %s in database %s: %w", a.table(), database, err)
+	}
+
+	if a.created == nil {
+		a.created = map[string]bool{}
+	}
+	a.created[database] = true
+
+	return nil
+}
+
+// record inserts a row for each statement of the transaction name.
+func (a *Audit) record(ctx context.Context, tx Querier, name string, statements []string) error {
+	table := a.table()
+
+	insert := fmt.Sprintf(`
+		INSERT INTO %s (executed_at, executed_by, operation, resource_type, object_name, service_call, statement)
+		VALUES (@ExecutedAt, CURRENT_USER, @Operation, @ResourceType, @ObjectName, @ServiceCall, @Statement)
+	`, table)
+
+	resourceType, operation := auditedOperation(ctx)
+	object, _ := ctx.Value(auditObjectKey{}).(string)
+	executedAt := time.Now().UTC()
+
+	for _, statement := range statements {
+		if isRead(statement) {
+			continue
+		}
+
+		_, err := tx.Exec(ctx, insert, pgx.NamedArgs{
+			"ExecutedAt":   executedAt,
+			"Operation":    operation,
+			"ResourceType": resourceType,
+			"ObjectName":   truncateBytes(object, maxObjectNameBytes),
+			"ServiceCall":  name,
+			"Statement":    truncateBytes(helpers.Redact(compactSQL(statement)), maxVarcharBytes),
+		})
+		if err != nil {
+			return fmt.Errorf("record: Failed to insert into audit table %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// auditedOperation splits the subsystem a resource names its context with,
+// such as user_resource.Create, into the resource type and the terraform
+// operation. Both are empty when no resource named it.
+func auditedOperation(ctx context.Context) (string, string) {
+	resource, operation, ok := strings.Cut(helpers.Subsystem(ctx), ".")
+	if !ok || !strings.HasSuffix(resource, "_resource") {
+		return "", ""
+	}
+
+	return "redshift_" + strings.TrimSuffix(resource, "_resource"), operation
+}
+
+// truncateBytes cuts s to at most n bytes without splitting a character.
+func truncateBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
@@ -0,0 +1,51 @@
+package redshift
+
+import (
+	"context"
+	"terraform-provider-redshift/internal/helpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_auditedOperation(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		ctx                  context.Context
+		expectedResourceType string
+		expectedOperation    string
+	}{
+		"resource": {
+			ctx:                  helpers.WithSubsystem(context.Background(), "rls_policy_attachment_resource.Delete"),
+			expectedResourceType: "redshift_rls_policy_attachment",
+			expectedOperation:    "Delete",
+		},
+		"not_a_resource": {
+			ctx: helpers.WithSubsystem(context.Background(), "redshift.retry"),
+		},
+		"unnamed": {
+			ctx: context.Background(),
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resourceType, operation := auditedOperation(test.ctx)
+			assert.Equal(t, test.expectedResourceType, resourceType)
+			assert.Equal(t, test.expectedOperation, operation)
+		})
+	}
+}
+
+func Test_truncateBytes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "GRANT", truncateBytes("GRANT", 5))
+	assert.Equal(t, "GRA", truncateBytes("GRANT", 3))
+	// é is two bytes and is not split
+	assert.Equal(t, "caf", truncateBytes("café", 4))
+}
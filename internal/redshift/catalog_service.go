@@ -0,0 +1,219 @@
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-redshift/internal/helpers"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// The kinds of principal which hold a name in the catalog.
+const (
+	PrincipalUser  = "user"
+	PrincipalGroup = "group"
+	PrincipalRole  = "role"
+)
+
+// CatalogService answers questions about what already exists in redshift,
+// it is used to check a plan before anything is applied.
+type CatalogService struct {
+	exec    Executor
+	ctx     context.Context
+	timeout time.Duration
+}
+
+func NewCatalogService(ctx context.Context, exec Executor) (*CatalogService, error) {
+	timeout := operationTimeout(ctx)
+
+	return &CatalogService{
+		exec:    exec,
+		ctx:     ctx,
+		timeout: timeout,
+	}, nil
+}
+
+// returns the kinds of principal which already have the name. Users and roles
+// are matched as redshift would fold the name, groups are created with case
+// sensitive identifiers and matched exactly.
+func (s *CatalogService) PrincipalsNamed(name string) ([]string, error) {
+	sql := `
+		SELECT 'user' AS kind
+		  FROM pg_user
+		 WHERE usename = @Name
+		 UNION ALL
+		SELECT 'role' AS kind
+		  FROM svv_roles
+		 WHERE role_name = @Name
+		 UNION ALL
+		SELECT 'group' AS kind
+		  FROM pg_group
+		 WHERE groname = @GroupName
+	`
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	var kinds []string
+	err := s.exec.InTx(ctx, "PrincipalsNamed", func(tx Querier) error {
+		caseSensitive, err := caseSensitiveIdentifiers(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("PrincipalsNamed: %w", err)
+		}
+
+		args := pgx.NamedArgs{"Name": helpers.NormalizeIdentifier(name, caseSensitive), "GroupName": name}
+		rows, err := tx.Query(ctx, sql, args)
+		if err != nil {
+			return fmt.Errorf("PrincipalsNamed: Failed query execute: %w", err)
+		}
+
+		kinds, err = pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return fmt.Errorf("PrincipalsNamed: Failed to collect rows: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return kinds, nil
+}
+
+// returns those of the usernames which no user has, as spelled by the caller.
+func (s *CatalogService) MissingUsers(usernames []string) ([]string, error) {
+	sql := `
+		SELECT usename
+		  FROM pg_user
+	`
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	var missing []string
+	err := s.exec.InTx(ctx, "MissingUsers", func(tx Querier) error {
+		caseSensitive, err := caseSensitiveIdentifiers(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("MissingUsers: %w", err)
+		}
+
+		rows, err := tx.Query(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("MissingUsers: Failed query execute: %w", err)
+		}
+
+		users, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return fmt.Errorf("MissingUsers: Failed to collect rows: %w", err)
+		}
+
+		known := map[string]bool{}
+		for _, user := range users {
+			known[user] = true
+		}
+
+		missing = nil
+		for _, username := range usernames {
+			if !known[helpers.NormalizeIdentifier(username, caseSensitive)] {
+				missing = append(missing, username)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
+
+// returns those of the usernames which are already members of the group, as
+// spelled by the caller. Users are matched as redshift would fold the name,
+// the group exactly. A group which does not exist has no members.
+func (s *CatalogService) MembersOf(groupName string, usernames []string) ([]string, error) {
+	sql := `
+		SELECT pu.usename
+		  FROM pg_user pu
+			   JOIN pg_group pg
+				 ON pu.usesysid = ANY ( pg.grolist )
+		 WHERE pg.groname = @GroupName
+	`
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	var found []string
+	err := s.exec.InTx(ctx, "MembersOf", func(tx Querier) error {
+		caseSensitive, err := caseSensitiveIdentifiers(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("MembersOf: %w", err)
+		}
+
+		rows, err := tx.Query(ctx, sql, pgx.NamedArgs{"GroupName": groupName})
+		if err != nil {
+			return fmt.Errorf("MembersOf: Failed query execute: %w", err)
+		}
+
+		members, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return fmt.Errorf("MembersOf: Failed to collect rows: %w", err)
+		}
+
+		known := map[string]bool{}
+		for _, member := range members {
+			known[member] = true
+		}
+
+		found = nil
+		for _, username := range usernames {
+			if known[helpers.NormalizeIdentifier(username, caseSensitive)] {
+				found = append(found, username)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// reports whether enable_case_sensitive_identifier is on, names which only
+// differ by case are then different principals.
+func (s *CatalogService) CaseSensitiveIdentifiers() (bool, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	var caseSensitive bool
+	err := s.exec.InTx(ctx, "CaseSensitiveIdentifiers", func(tx Querier) error {
+		var err error
+		caseSensitive, err = caseSensitiveIdentifiers(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("CaseSensitiveIdentifiers: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return caseSensitive, nil
+}
+
+// reads the cluster setting without changing it for the session.
+func caseSensitiveIdentifiers(ctx context.Context, tx Querier) (bool, error) {
+	var setting string
+
+	err := tx.QueryRow(ctx, "SELECT current_setting('enable_case_sensitive_identifier')").Scan(&setting)
+	if err != nil {
+		return false, fmt.Errorf("caseSensitiveIdentifiers: Failed query execute: %w", err)
+	}
+
+	return setting == "on" || setting == "true", nil
+}
@@ -0,0 +1,138 @@
+package redshift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	principalsNamedSQL = `
+		SELECT 'user' AS kind
+		  FROM pg_user
+		 WHERE usename = @Name
+		 UNION ALL
+		SELECT 'role' AS kind
+		  FROM svv_roles
+		 WHERE role_name = @Name
+		 UNION ALL
+		SELECT 'group' AS kind
+		  FROM pg_group
+		 WHERE groname = @GroupName
+	`
+	allUsernamesSQL = `
+		SELECT usename
+		  FROM pg_user
+	`
+)
+
+func Test_CatalogService_PrincipalsNamed(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		setting  string
+		name     string
+		args     pgx.NamedArgs
+		kinds    []string
+		expected []string
+	}{
+		"free": {
+			setting: "off",
+			name:    "analysts",
+			args:    pgx.NamedArgs{"Name": "analysts", "GroupName": "analysts"},
+		},
+		"folded_for_users_and_roles": {
+			setting:  "off",
+			name:     "Analysts",
+			args:     pgx.NamedArgs{"Name": "analysts", "GroupName": "Analysts"},
+			kinds:    []string{PrincipalUser, PrincipalGroup},
+			expected: []string{PrincipalUser, PrincipalGroup},
+		},
+		"case_sensitive_cluster": {
+			setting:  "on",
+			name:     "Analysts",
+			args:     pgx.NamedArgs{"Name": "Analysts", "GroupName": "Analysts"},
+			kinds:    []string{PrincipalRole},
+			expected: []string{PrincipalRole},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			executor, mock := newMockExecutor(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(caseSensitiveSettingSQL).
+				WillReturnRows(pgxmock.NewRows([]string{"current_setting"}).AddRow(test.setting))
+			rows := pgxmock.NewRows([]string{"kind"})
+			for _, kind := range test.kinds {
+				rows.AddRow(kind)
+			}
+			mock.ExpectQuery(principalsNamedSQL).WithArgs(test.args).WillReturnRows(rows)
+			mock.ExpectCommit()
+
+			svc, err := NewCatalogService(context.Background(), executor)
+			if err != nil {
+				t.Fatalf("failed to create service: %s", err)
+			}
+
+			kinds, err := svc.PrincipalsNamed(test.name)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, test.expected, kinds)
+		})
+	}
+}
+
+func Test_CatalogService_MissingUsers(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		setting   string
+		usernames []string
+		expected  []string
+	}{
+		"all_exist": {
+			setting:   "off",
+			usernames: []string{"Alice", "bob"},
+		},
+		"some_missing": {
+			setting:   "off",
+			usernames: []string{"alice", "Dave", "erin"},
+			expected:  []string{"Dave", "erin"},
+		},
+		"case_sensitive_cluster": {
+			setting:   "on",
+			usernames: []string{"Alice", "bob"},
+			expected:  []string{"Alice"},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			executor, mock := newMockExecutor(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(caseSensitiveSettingSQL).
+				WillReturnRows(pgxmock.NewRows([]string{"current_setting"}).AddRow(test.setting))
+			mock.ExpectQuery(allUsernamesSQL).
+				WillReturnRows(pgxmock.NewRows([]string{"usename"}).AddRow("alice").AddRow("bob"))
+			mock.ExpectCommit()
+
+			svc, err := NewCatalogService(context.Background(), executor)
+			if err != nil {
+				t.Fatalf("failed to create service: %s", err)
+			}
+
+			missing, err := svc.MissingUsers(test.usernames)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, missing)
+		})
+	}
+}
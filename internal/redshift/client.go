@@ -0,0 +1,324 @@
+package redshift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"terraform-provider-redshift/internal/helpers"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/tracelog"
+)
+
+const (
+	DefaultMaxRetries   = 3
+	DefaultRetryBackoff = time.Second
+	// bounds an operation of a service whose context has no deadline, and
+	// is the default of the resources' timeouts
+	DefaultTimeout = 5 * time.Minute
+)
+
+// Querier is the part of a transaction the services use.
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Executor is how the services reach the cluster, *Client in the provider
+// and a mock in the services' unit tests.
+type Executor interface {
+	// runs fn in a transaction, which is committed when fn returns nil.
+	// name prefixes the errors returned.
+	InTx(ctx context.Context, name string, fn func(tx Querier) error) error
+	// the executor for another database of the cluster, the same one when
+	// database is empty
+	ForDatabase(database string) Executor
+}
+
+// Client is what the provider hands to every resource, the services reach
+// the cluster through it. Transactions run on a pool of connections to the
+// client's database, which ForDatabase switches.
+type Client struct {
+	ConnCfg *pgx.ConnConfig
+	// how often a transaction which failed with a transient error is run again
+	MaxRetries int
+	// the wait before the first retry, doubled for each one after
+	RetryBackoff time.Duration
+	// when set, adjusts a copy of ConnCfg before each connection, such as
+	// with credentials which expire
+	BeforeConnect func(ctx context.Context, cfg *pgx.ConnConfig) error
+	// set on each session, zero leaves the cluster's default
+	StatementTimeout time.Duration
+	// refuses every statement which is not a read with ErrReadOnly, and
+	// makes each session read only
+	ReadOnly bool
+	// when set, the statements of each transaction which changes redshift
+	// are appended to it
+	SQLOutput *SQLOutput
+	// when set, the statements of each transaction which changes redshift
+	// are recorded in it before the transaction commits
+	Audit *Audit
+
+	// the database transactions run in, ConnCfg.Database when empty
+	database string
+	pools    *pools
+}
+
+// pools holds a pool per database name, shared by a client and those
+// ForDatabase derives from it. A pool is created on first use.
+type pools struct {
+	mu         sync.Mutex
+	byDatabase map[string]*pgxpool.Pool
+}
+
+func NewClient(cfg *pgx.ConnConfig) *Client {
+	return &Client{
+		ConnCfg:      cfg,
+		MaxRetries:   DefaultMaxRetries,
+		RetryBackoff: DefaultRetryBackoff,
+		pools:        &pools{byDatabase: map[string]*pgxpool.Pool{}},
+	}
+}
+
+// ForDatabase is the client for another database of the cluster, database
+// scoped objects such as schemas and grants live in one. An empty database
+// is the one the provider connects to.
+func (c *Client) ForDatabase(database string) Executor {
+	if database == "" {
+		return c
+	}
+
+	other := *c
+	other.database = database
+
+	return &other
+}
+
+// Database is the database transactions of the client run in.
+func (c *Client) Database() string {
+	if c.database != "" {
+		return c.database
+	}
+
+	return c.ConnCfg.Database
+}
+
+// Close closes the pools of every database.
+func (c *Client) Close() {
+	c.pools.mu.Lock()
+	defer c.pools.mu.Unlock()
+
+	for database, pool := range c.pools.byDatabase {
+		pool.Close()
+		delete(c.pools.byDatabase, database)
+	}
+}
+
+var _ Executor = &Client{}
+
+// InTx runs fn in a transaction on a connection from the pool of the client's
+// database. A transient failure rolls the transaction back and runs fn again,
+// so fn must not keep state between runs. name prefixes the errors returned.
+func (c *Client) InTx(ctx context.Context, name string, fn func(tx Querier) error) error {
+	backoff := c.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := c.runTx(ctx, name, fn)
+		if err == nil {
+			return nil
+		}
+
+		var commitErr *ambiguousCommitError
+		if errors.As(err, &commitErr) || attempt >= c.MaxRetries || !IsRetryable(err) {
+			return err
+		}
+
+		helpers.NewLogger(ctx, "redshift.retry").Log(ctx, tracelog.LogLevelWarn, "Retrying transaction after a transient error", map[string]interface{}{
+			"operation":   name,
+			"attempt":     attempt + 1,
+			"max_retries": c.MaxRetries,
+			"backoff":     backoff.String(),
+			"error":       err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s: Gave up retrying: %w", name, err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// operationTimeout bounds each operation of a service created with ctx. The
+// deadline of ctx, such as from the resource's timeouts, is left in charge.
+func operationTimeout(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+
+	tflog.Info(ctx, "No timeout provided, using "+DefaultTimeout.String())
+
+	return DefaultTimeout
+}
+
+// pool is the pool of the client's database, created on first use. Creating
+// it does not connect, connections are made as transactions need them.
+func (c *Client) pool() (*pgxpool.Pool, error) {
+	database := c.Database()
+
+	c.pools.mu.Lock()
+	defer c.pools.mu.Unlock()
+
+	if pool, ok := c.pools.byDatabase[database]; ok {
+		return pool, nil
+	}
+
+	// a pool config must come from ParseConfig, its connection config is
+	// replaced by the provider's, so the environment has no say in it
+	var poolCfg *pgxpool.Config
+	err := WithoutEnvironment(func() (err error) {
+		poolCfg, err = pgxpool.ParseConfig("")
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pool: Failed to create pool config: %w", err)
+	}
+	poolCfg.ConnConfig = c.ConnCfg.Copy()
+	poolCfg.ConnConfig.Database = database
+	poolCfg.BeforeConnect = c.BeforeConnect
+	poolCfg.AfterConnect = c.configureSession
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("pool: Failed to create pool for database %s: %w", database, err)
+	}
+	c.pools.byDatabase[database] = pool
+
+	return pool, nil
+}
+
+// configureSession applies the client's settings to each new connection.
+func (c *Client) configureSession(ctx context.Context, conn *pgx.Conn) error {
+	if c.StatementTimeout > 0 {
+		_, err := conn.Exec(ctx, fmt.Sprintf("SET statement_timeout TO %d", c.StatementTimeout.Milliseconds()))
+		if err != nil {
+			return fmt.Errorf("configureSession: Failed to set statement_timeout: %w", err)
+		}
+	}
+
+	if c.ReadOnly {
+		_, err := conn.Exec(ctx, "SET default_transaction_read_only TO on")
+		if err != nil {
+			return fmt.Errorf("configureSession: Failed to set default_transaction_read_only: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CreateAuditTable creates the audit table in the client's database if it is
+// missing. The table of any other database is created before the first
+// transaction there.
+func (c *Client) CreateAuditTable(ctx context.Context) error {
+	pool, err := c.pool()
+	if err != nil {
+		return fmt.Errorf("CreateAuditTable: Unable to connect %w", err)
+	}
+
+	return c.Audit.create(ctx, c.Database(), pool)
+}
+
+func (c *Client) runTx(ctx context.Context, name string, fn func(tx Querier) error) error {
+	pool, err := c.pool()
+	if err != nil {
+		return fmt.Errorf("%s: Unable to connect %w", name, err)
+	}
+
+	// nothing is recorded when nothing can change
+	if c.Audit != nil && !c.ReadOnly && (c.SQLOutput == nil || !c.SQLOutput.Only) {
+		if err := c.Audit.create(ctx, c.Database(), pool); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	// the connection returns to the pool once the transaction ends
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: Unable to connect %w", name, err)
+	}
+	// a no-op once committed
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	var querier Querier = tx
+	if c.ReadOnly {
+		querier = readOnlyQuerier{tx}
+	}
+
+	var capture *captureQuerier
+	if c.SQLOutput != nil || c.Audit != nil {
+		capture = &captureQuerier{tx: querier, only: c.SQLOutput != nil && c.SQLOutput.Only}
+		querier = capture
+	}
+
+	err = fn(querier)
+	if capture != nil && capture.only && capture.changed() {
+		// the reads after a statement which did not run may fail, which
+		// says nothing about the statements
+		if err := c.SQLOutput.write(name, c.Database(), capture.statements); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		return fmt.Errorf("%s: %w", name, ErrSQLOutputOnly)
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.Audit != nil && capture.changed() {
+		// the records commit or roll back with the statements
+		if err := c.Audit.record(ctx, tx, name, capture.statements); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		// the server may have committed before the connection was lost
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) {
+			return &ambiguousCommitError{fmt.Errorf("%s: Failed to commit: %w", name, err)}
+		}
+
+		return fmt.Errorf("%s: Failed to commit: %w", name, err)
+	}
+
+	if c.SQLOutput != nil && capture.changed() {
+		// too late to roll back, but not retried as nothing here is
+		// transient
+		if err := c.SQLOutput.write(name, c.Database(), capture.statements); err != nil {
+			return fmt.Errorf("%s: Committed, but failed to record the statements: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// a commit whose outcome is unknown, never retried.
+type ambiguousCommitError struct {
+	err error
+}
+
+func (e *ambiguousCommitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ambiguousCommitError) Unwrap() error {
+	return e.err
+}
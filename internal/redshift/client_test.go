@@ -0,0 +1,78 @@
+package redshift
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Client_ForDatabase(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := pgx.ParseConfig("postgres://admin@127.0.0.1:5439/dev?sslmode=disable")
+	if err != nil {
+		t.Fatalf("failed to parse config: %s", err)
+	}
+
+	client := NewClient(cfg)
+	defer client.Close()
+
+	analytics := client.ForDatabase("analytics").(*Client)
+	assert.Equal(t, "analytics", analytics.Database())
+	assert.Equal(t, "dev", client.Database())
+	assert.Same(t, client, client.ForDatabase(""))
+
+	// pools are created without connecting, one per database
+	devPool, err := client.pool()
+	assert.NoError(t, err)
+	analyticsPool, err := analytics.pool()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "dev", devPool.Config().ConnConfig.Database)
+	assert.Equal(t, "analytics", analyticsPool.Config().ConnConfig.Database)
+
+	again, err := client.ForDatabase("analytics").(*Client).pool()
+	assert.NoError(t, err)
+	assert.Same(t, analyticsPool, again)
+	assert.Len(t, client.pools.byDatabase, 2)
+
+	// the provider's config is left as it is
+	assert.Equal(t, "dev", cfg.Database)
+}
+
+func Test_Client_pool_environment(t *testing.T) {
+	// not parallel, it sets environment variables
+	cfg, err := pgx.ParseConfig("postgres://admin@127.0.0.1:5439/dev?sslmode=disable")
+	if err != nil {
+		t.Fatalf("failed to parse config: %s", err)
+	}
+
+	t.Setenv("PGSSLMODE", "bogus")
+	t.Setenv("PGCONNECT_TIMEOUT", "1")
+
+	client := NewClient(cfg)
+	defer client.Close()
+
+	// the pool takes its connection config from the client alone
+	pool, err := client.pool()
+	if assert.NoError(t, err) {
+		assert.Zero(t, pool.Config().ConnConfig.ConnectTimeout)
+	}
+}
+
+func Test_operationTimeout(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, DefaultTimeout, operationTimeout(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Minute)
+	defer cancel()
+
+	// the caller's deadline is kept, even beyond the default
+	timeout := operationTimeout(ctx)
+	assert.Greater(t, timeout, DefaultTimeout)
+	assert.LessOrEqual(t, timeout, 20*time.Minute)
+}
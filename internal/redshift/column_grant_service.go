@@ -0,0 +1,231 @@
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"terraform-provider-redshift/internal/helpers"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type svv_column_privileges struct {
+	PrivilegeType string `db:"privilege_type"`
+	ColumnName    string `db:"column_name"`
+}
+
+type ColumnGrant struct {
+	SchemaName  string
+	TableName   string
+	GranteeType string
+	Grantee     string
+	// columns granted, keyed by privilege (SELECT or UPDATE)
+	Columns map[string][]string
+}
+
+type ColumnGrantService struct {
+	exec    Executor
+	ctx     context.Context
+	timeout time.Duration
+}
+
+func NewColumnGrantService(ctx context.Context, exec Executor) (*ColumnGrantService, error) {
+	timeout := operationTimeout(ctx)
+
+	return &ColumnGrantService{
+		exec:    exec,
+		ctx:     ctx,
+		timeout: timeout,
+	}, nil
+}
+
+type ColumnGrantDDLParams struct {
+	SchemaName string
+	TableName  string
+	// one of user, group, role or public
+	GranteeType string
+	Grantee     string
+}
+
+func (s *ColumnGrantService) FindColumnGrant(args ColumnGrantDDLParams) (*ColumnGrant, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	var grant *ColumnGrant
+	err := s.exec.InTx(ctx, "FindColumnGrant", func(tx Querier) error {
+		var err error
+		grant, err = getColumnGrant(args, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("FindColumnGrant: Failed to build column grant: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return grant, nil
+}
+
+type AlterColumnGrantDDLParams struct {
+	ColumnGrantDDLParams
+	// columns to grant and revoke, keyed by privilege (SELECT or UPDATE)
+	Grant  map[string][]string
+	Revoke map[string][]string
+}
+
+func (s *ColumnGrantService) AlterColumnGrant(args AlterColumnGrantDDLParams) (*ColumnGrant, error) {
+	grant := `
+		GRANT {{.Privilege}} ({{(StringsJoin .Columns ", ")}})
+			ON {{.Table}}
+			TO {{.Grantee}}
+	`
+	revoke := `
+		REVOKE {{.Privilege}} ({{(StringsJoin .Columns ", ")}})
+			ON {{.Table}}
+			FROM {{.Grantee}}
+	`
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, auditName(args.SchemaName, args.TableName))
+
+	var columnGrant *ColumnGrant
+	err := s.exec.InTx(ctx, "AlterColumnGrant", func(tx Querier) error {
+		var err error
+		// redshift must grant and revoke each privilege separately
+		for _, privilege := range sortedPrivileges(args.Revoke) {
+			sql, err := helpers.Merge(revoke, columnGrantTemplateParams(args.ColumnGrantDDLParams, privilege, args.Revoke[privilege]))
+			if err != nil {
+				return fmt.Errorf("AlterColumnGrant: failed to merge revoke template: %w", err)
+			}
+
+			_, err = tx.Exec(ctx, sql)
+			if err != nil {
+				return fmt.Errorf("AlterColumnGrant: failed to execute revoke: %w", err)
+			}
+		}
+
+		for _, privilege := range sortedPrivileges(args.Grant) {
+			sql, err := helpers.Merge(grant, columnGrantTemplateParams(args.ColumnGrantDDLParams, privilege, args.Grant[privilege]))
+			if err != nil {
+				return fmt.Errorf("AlterColumnGrant: failed to merge grant template: %w", err)
+			}
+
+			_, err = tx.Exec(ctx, sql)
+			if err != nil {
+				return fmt.Errorf("AlterColumnGrant: failed to execute grant: %w", err)
+			}
+		}
+
+		columnGrant, err = getColumnGrant(args.ColumnGrantDDLParams, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("AlterColumnGrant: Failed to getColumnGrant: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return columnGrant, nil
+}
+
+type columnGrantParams struct {
+	Privilege string
+	Columns   []string
+	Table     string
+	Grantee   string
+}
+
+func columnGrantTemplateParams(args ColumnGrantDDLParams, privilege string, columns []string) columnGrantParams {
+	params := columnGrantParams{
+		Privilege: strings.ToUpper(privilege),
+		Table:     pgx.Identifier{args.SchemaName, args.TableName}.Sanitize(),
+	}
+
+	sorted := append([]string{}, columns...)
+	sort.Strings(sorted)
+	for _, column := range sorted {
+		params.Columns = append(params.Columns, pgx.Identifier{column}.Sanitize())
+	}
+
+	switch args.GranteeType {
+	case "public":
+		params.Grantee = "PUBLIC"
+	case "group":
+		params.Grantee = "GROUP " + pgx.Identifier{args.Grantee}.Sanitize()
+	case "role":
+		params.Grantee = "ROLE " + pgx.Identifier{args.Grantee}.Sanitize()
+	default:
+		params.Grantee = pgx.Identifier{args.Grantee}.Sanitize()
+	}
+
+	return params
+}
+
+// the privileges with at least one column, in a stable order.
+func sortedPrivileges(columns map[string][]string) []string {
+	var privileges []string
+	for privilege, cols := range columns {
+		if len(cols) > 0 {
+			privileges = append(privileges, privilege)
+		}
+	}
+	sort.Strings(privileges)
+
+	return privileges
+}
+
+func getColumnGrant(args ColumnGrantDDLParams, ctx context.Context, tx Querier) (*ColumnGrant, error) {
+	sql := `
+	SELECT svv.privilege_type,
+		   svv.column_name
+	  FROM svv_column_privileges svv
+	 WHERE svv.namespace_name = @SchemaName
+	   AND svv.relation_name = @TableName
+	   AND svv.identity_type = @GranteeType
+	   AND svv.identity_name = @Grantee
+	 ORDER BY svv.privilege_type,
+			  svv.column_name
+	`
+	grantee := args.Grantee
+	if args.GranteeType == "public" {
+		grantee = "public"
+	}
+
+	namedArgs := pgx.NamedArgs{
+		"SchemaName":  args.SchemaName,
+		"TableName":   args.TableName,
+		"GranteeType": args.GranteeType,
+		"Grantee":     grantee,
+	}
+
+	rows, err := tx.Query(ctx, sql, namedArgs)
+	if err != nil {
+		return nil, fmt.Errorf("getColumnGrant: Failed query execute: %w", err)
+	}
+
+	privileges, err := pgx.CollectRows(rows, pgx.RowToStructByName[svv_column_privileges])
+	if err != nil {
+		return nil, fmt.Errorf("getColumnGrant: Failed to collect rows: %w", err)
+	}
+
+	grant := ColumnGrant{
+		SchemaName:  args.SchemaName,
+		TableName:   args.TableName,
+		GranteeType: args.GranteeType,
+		Grantee:     args.Grantee,
+		Columns:     map[string][]string{},
+	}
+	for _, privilege := range privileges {
+		key := strings.ToUpper(privilege.PrivilegeType)
+		grant.Columns[key] = append(grant.Columns[key], privilege.ColumnName)
+	}
+
+	return &grant, nil
+}
@@ -0,0 +1,164 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"terraform-provider-redshift/internal/helpers"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// The DDL is rendered here, apart from the services, so that what is sent to
+// redshift can be checked without a database. Every Render function takes the
+// unsanitized names from the DDL params and quotes them itself.
+
+func RenderCreateUser(args CreateUserDDLParams) (string, error) {
+	t := `
+		CREATE USER {{.Name}}
+		    PASSWORD {{if .Password}}'{{.Password}}'{{else}}DISABLE{{end}}
+			{{if .CreateDb}}CREATEDB{{else}}NOCREATEDB{{end}}
+			{{if .CreateUser}}CREATEUSER{{else}}NOCREATEUSER{{end}}
+			SYSLOG ACCESS {{.SyslogAccess}}
+			VALID UNTIL '{{.ValidUntil}}'
+			CONNECTION LIMIT {{.ConnectionLimit}}
+			{{if gt .SessionTimeout 0}}SESSION TIMEOUT {{.SessionTimeout}}{{end}}
+			{{if .ExternalId}}EXTERNALID '{{.ExternalId}}' {{end}}
+	`
+	args.Name = pgx.Identifier{args.Name}.Sanitize()
+
+	return render("RenderCreateUser", t, args)
+}
+
+// returns the statements in the order they must run, redshift will only
+// rename a user in a statement of its own.
+func RenderAlterUser(args AlterUserDDLParams) ([]string, error) {
+	args.Name = pgx.Identifier{args.Name}.Sanitize()
+
+	var statements []string
+
+	// Undocumented, redshift must perform a rename without other options; it is a syntax error otherwise
+	if args.RenameTo != nil {
+		rn := pgx.Identifier{*args.RenameTo}.Sanitize()
+		args.RenameTo = &rn
+
+		rename := `
+			ALTER USER {{.Name}} RENAME TO {{.RenameTo}}
+		`
+		sql, err := render("RenderAlterUser", rename, args)
+		if err != nil {
+			return nil, err
+		}
+
+		statements = append(statements, sql)
+	}
+
+	// a rename on its own leaves nothing else to alter
+	if args.Password != nil || args.CreateDb != nil || args.CreateUser != nil || args.SyslogAccess != nil ||
+		args.ValidUntil != nil || args.ConnectionLimit != nil || args.SessionTimeout != nil || args.ExternalId != nil {
+		t := `
+			ALTER USER {{if .RenameTo}}{{.RenameTo}}{{else}}{{.Name}}{{end}}
+			    {{if .Password}}PASSWORD {{if (DerefString .Password)}}'{{.Password}}'{{else}}DISABLE{{end}}{{end}}
+				{{if .CreateDb}}{{if (DerefBool .CreateDb)}}CREATEDB{{else}}NOCREATEDB{{end}}{{end}}
+				{{if .CreateUser}}{{if (DerefBool .CreateUser)}}CREATEUSER{{else}}NOCREATEUSER{{end}}{{end}}
+				{{if .SyslogAccess}}SYSLOG ACCESS {{.SyslogAccess}}{{end}}
+				{{if .ValidUntil}}VALID UNTIL '{{.ValidUntil}}'{{end}}
+				{{if .ConnectionLimit}}CONNECTION LIMIT {{.ConnectionLimit}}{{end}}
+				{{if .SessionTimeout}}{{if gt (DerefInt64 .SessionTimeout) 0}}SESSION TIMEOUT {{.SessionTimeout}}{{else}}RESET SESSION TIMEOUT{{end}}{{end}}
+				{{if .ExternalId}}EXTERNALID '{{.ExternalId}}' {{end}}
+			`
+		sql, err := render("RenderAlterUser", t, args)
+		if err != nil {
+			return nil, err
+		}
+
+		statements = append(statements, sql)
+	}
+
+	return statements, nil
+}
+
+// usernames are folded to lower case unless the cluster has case sensitive
+// identifiers, users are created without them.
+func RenderCreateGroup(args CreateGroupDDLParams, caseSensitive bool) (string, error) {
+	t := `
+		CREATE GROUP {{.Name}}
+			{{if .Usernames}}{{$length := len .Usernames}}{{if gt $length 0 }}WITH USER {{(StringsJoin .Usernames ", ")}}{{end}}{{end}}
+	`
+	args.Name = pgx.Identifier{args.Name}.Sanitize()
+
+	if args.Usernames != nil && len(*args.Usernames) > 0 {
+		sanitized := []string{}
+
+		for _, username := range *args.Usernames {
+			sanitized = append(sanitized, pgx.Identifier{helpers.NormalizeIdentifier(username, caseSensitive)}.Sanitize())
+		}
+
+		args.Usernames = &sanitized
+	}
+
+	return render("RenderCreateGroup", t, args)
+}
+
+func RenderCreateRole(args CreateRoleDDLParams) (string, error) {
+	t := `
+		CREATE ROLE {{.Name}}
+			{{if .ExternalId}}EXTERNALID '{{.ExternalId}}' {{end}}
+	`
+	args.Name = pgx.Identifier{args.Name}.Sanitize()
+
+	return render("RenderCreateRole", t, args)
+}
+
+func RenderAlterRole(args AlterRoleDDLParams) (string, error) {
+	t := `
+		ALTER ROLE {{.Name}}
+			{{if .RenameTo}}RENAME TO {{.RenameTo}}{{end}}
+			{{if .ExternalId}}EXTERNALID '{{.ExternalId}}' {{end}}
+	`
+	args.Name = pgx.Identifier{args.Name}.Sanitize()
+	if args.RenameTo != nil {
+		rn := pgx.Identifier{*args.RenameTo}.Sanitize()
+		args.RenameTo = &rn
+	}
+
+	return render("RenderAlterRole", t, args)
+}
+
+func render(name string, t string, args any) (string, error) {
+	sql, err := helpers.Merge(t, args)
+	if err != nil {
+		return "", fmt.Errorf("%s: Failed to merge template: %w", name, err)
+	}
+
+	return compactSQL(sql), nil
+}
+
+// collapses the indentation and blank clauses left by a template into single
+// spaces, leaving anything inside quotes as it was.
+func compactSQL(sql string) string {
+	var b strings.Builder
+	var quote rune
+	space := false
+
+	for _, r := range strings.TrimSpace(sql) {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			space = true
+			continue
+		}
+
+		if space {
+			b.WriteRune(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,162 @@
+package redshift
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func Test_RenderDDL(t *testing.T) {
+	t.Parallel()
+
+	password := "Secret123"
+	spacedPassword := "two  spaces"
+	noPassword := ""
+	externalId := "ext-1"
+	yes := true
+	no := false
+	syslog := "UNRESTRICTED"
+	validUntil := "2030-01-01 00:00:00"
+	limit := "5"
+	timeout := int64(120)
+	resetTimeout := int64(0)
+	renamed := "robert"
+	odd := `odd "name" with spaces`
+	mixedCase := "Analysts"
+	usernames := []string{"Alice", "bob"}
+	oddUsernames := []string{`o"brien`, "with space"}
+	noUsernames := []string{}
+
+	createUser := func(args CreateUserDDLParams) func() ([]string, error) {
+		return func() ([]string, error) {
+			sql, err := RenderCreateUser(args)
+			return []string{sql}, err
+		}
+	}
+	user := func(args CreateUserDDLParams) CreateUserDDLParams {
+		args.Name = "bob"
+		args.SyslogAccess = "RESTRICTED"
+		args.ValidUntil = "infinity"
+		args.ConnectionLimit = "UNLIMITED"
+		return args
+	}
+	alterUser := func(args AlterUserDDLParams) func() ([]string, error) {
+		return func() ([]string, error) {
+			if args.Name == "" {
+				args.Name = "bob"
+			}
+			return RenderAlterUser(args)
+		}
+	}
+	createGroup := func(args CreateGroupDDLParams, caseSensitive bool) func() ([]string, error) {
+		return func() ([]string, error) {
+			sql, err := RenderCreateGroup(args, caseSensitive)
+			return []string{sql}, err
+		}
+	}
+	createRole := func(args CreateRoleDDLParams) func() ([]string, error) {
+		return func() ([]string, error) {
+			sql, err := RenderCreateRole(args)
+			return []string{sql}, err
+		}
+	}
+	alterRole := func(args AlterRoleDDLParams) func() ([]string, error) {
+		return func() ([]string, error) {
+			sql, err := RenderAlterRole(args)
+			return []string{sql}, err
+		}
+	}
+
+	tests := map[string]func() ([]string, error){
+		"create_user_minimal":          createUser(user(CreateUserDDLParams{})),
+		"create_user_password":         createUser(user(CreateUserDDLParams{Password: &password})),
+		"create_user_password_spaces":  createUser(user(CreateUserDDLParams{Password: &spacedPassword})),
+		"create_user_createdb":         createUser(user(CreateUserDDLParams{CreateDb: true})),
+		"create_user_createuser":       createUser(user(CreateUserDDLParams{CreateUser: true})),
+		"create_user_session_timeout":  createUser(user(CreateUserDDLParams{SessionTimeout: timeout})),
+		"create_user_external_id":      createUser(user(CreateUserDDLParams{ExternalId: &externalId})),
+		"create_user_odd_identifier":   createUser(CreateUserDDLParams{Name: odd, SyslogAccess: "RESTRICTED", ValidUntil: "infinity", ConnectionLimit: "UNLIMITED"}),
+		"create_user_all":              createUser(CreateUserDDLParams{Name: "bob", Password: &password, CreateDb: true, CreateUser: true, SyslogAccess: syslog, ValidUntil: validUntil, ConnectionLimit: limit, SessionTimeout: timeout, ExternalId: &externalId}),
+		"alter_user_nothing":           alterUser(AlterUserDDLParams{}),
+		"alter_user_rename_only":       alterUser(AlterUserDDLParams{RenameTo: &renamed}),
+		"alter_user_rename_and_limit":  alterUser(AlterUserDDLParams{RenameTo: &renamed, ConnectionLimit: &limit}),
+		"alter_user_password":          alterUser(AlterUserDDLParams{Password: &password}),
+		"alter_user_password_disable":  alterUser(AlterUserDDLParams{Password: &noPassword}),
+		"alter_user_createdb":          alterUser(AlterUserDDLParams{CreateDb: &yes}),
+		"alter_user_nocreatedb":        alterUser(AlterUserDDLParams{CreateDb: &no}),
+		"alter_user_createuser":        alterUser(AlterUserDDLParams{CreateUser: &yes}),
+		"alter_user_nocreateuser":      alterUser(AlterUserDDLParams{CreateUser: &no}),
+		"alter_user_syslog_access":     alterUser(AlterUserDDLParams{SyslogAccess: &syslog}),
+		"alter_user_valid_until":       alterUser(AlterUserDDLParams{ValidUntil: &validUntil}),
+		"alter_user_connection_limit":  alterUser(AlterUserDDLParams{ConnectionLimit: &limit}),
+		"alter_user_session_timeout":   alterUser(AlterUserDDLParams{SessionTimeout: &timeout}),
+		"alter_user_reset_session":     alterUser(AlterUserDDLParams{SessionTimeout: &resetTimeout}),
+		"alter_user_external_id":       alterUser(AlterUserDDLParams{ExternalId: &externalId}),
+		"alter_user_odd_identifiers":   alterUser(AlterUserDDLParams{Name: odd, RenameTo: &mixedCase, Password: &password}),
+		"alter_user_all":               alterUser(AlterUserDDLParams{RenameTo: &renamed, Password: &password, CreateDb: &yes, CreateUser: &no, SyslogAccess: &syslog, ValidUntil: &validUntil, ConnectionLimit: &limit, SessionTimeout: &resetTimeout, ExternalId: &externalId}),
+		"create_group":                 createGroup(CreateGroupDDLParams{Name: "admins"}, false),
+		"create_group_no_users":        createGroup(CreateGroupDDLParams{Name: "admins", Usernames: &noUsernames}, false),
+		"create_group_users":           createGroup(CreateGroupDDLParams{Name: mixedCase, Usernames: &usernames}, false),
+		"create_group_users_sensitive": createGroup(CreateGroupDDLParams{Name: mixedCase, Usernames: &usernames}, true),
+		"create_group_odd_identifiers": createGroup(CreateGroupDDLParams{Name: odd, Usernames: &oddUsernames}, false),
+		"create_role":                  createRole(CreateRoleDDLParams{Name: "readers"}),
+		"create_role_external_id":      createRole(CreateRoleDDLParams{Name: "readers", ExternalId: &externalId}),
+		"create_role_odd_identifier":   createRole(CreateRoleDDLParams{Name: odd}),
+		"alter_role_rename":            alterRole(AlterRoleDDLParams{Name: "readers", RenameTo: &mixedCase}),
+		"alter_role_external_id":       alterRole(AlterRoleDDLParams{Name: "readers", ExternalId: &externalId}),
+		"alter_role_rename_external":   alterRole(AlterRoleDDLParams{Name: "readers", RenameTo: &mixedCase, ExternalId: &externalId}),
+		"alter_role_odd_identifier":    alterRole(AlterRoleDDLParams{Name: odd, RenameTo: &renamed}),
+	}
+
+	for name, render := range tests {
+		name, render := name, render
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			statements, err := render()
+			if err != nil {
+				t.Fatalf("failed to render: %s", err)
+			}
+
+			var actual string
+			for _, statement := range statements {
+				actual += statement + ";\n"
+			}
+
+			assertGolden(t, filepath.Join("testdata", name+".sql"), actual)
+		})
+	}
+}
+
+func Test_compactSQL(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `ALTER USER "a  b" PASSWORD 'x	 y'`, compactSQL("\n\t\tALTER USER \"a  b\"\n\t\t\tPASSWORD 'x\t y'  \n\t"))
+	assert.Equal(t, `CREATE ROLE "it's"`, compactSQL(`CREATE   ROLE "it's"`))
+	assert.Equal(t, `SELECT 'it''s  ok'`, compactSQL(`SELECT   'it''s  ok'`))
+}
+
+// compares with the golden file, or rewrites it when run with -update.
+func assertGolden(t *testing.T, golden string, actual string) {
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(golden, []byte(actual), 0o644); err != nil {
+			t.Fatalf("failed to update %s: %s", golden, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read %s, run with -update to create it: %s", golden, err)
+	}
+
+	assert.Equal(t, strings.ReplaceAll(string(expected), "\r\n", "\n"), actual)
+}
@@ -0,0 +1,58 @@
+package redshift
+
+import (
+	"os"
+	"sync"
+)
+
+// pgEnvironment lists the variables pgx reads when it parses a connection
+// string.
+var pgEnvironment = []string{
+	"PGHOST",
+	"PGPORT",
+	"PGDATABASE",
+	"PGUSER",
+	"PGPASSWORD",
+	"PGPASSFILE",
+	"PGAPPNAME",
+	"PGCONNECT_TIMEOUT",
+	"PGSSLMODE",
+	"PGSSLKEY",
+	"PGSSLCERT",
+	"PGSSLSNI",
+	"PGSSLROOTCERT",
+	"PGSSLPASSWORD",
+	"PGTARGETSESSIONATTRS",
+	"PGSERVICE",
+	"PGSERVICEFILE",
+}
+
+// envMu keeps WithEnvironment from running while WithoutEnvironment has
+// cleared the environment.
+var envMu sync.RWMutex
+
+// WithoutEnvironment runs parse with the PG* environment variables unset.
+// pgx offers no option to skip them, and only a config it parsed itself can
+// connect.
+func WithoutEnvironment(parse func() error) error {
+	envMu.Lock()
+	defer envMu.Unlock()
+
+	for _, name := range pgEnvironment {
+		if value, ok := os.LookupEnv(name); ok {
+			os.Unsetenv(name)
+			defer os.Setenv(name, value)
+		}
+	}
+
+	return parse()
+}
+
+// WithEnvironment runs parse with the PG* environment variables as they are
+// set for the process.
+func WithEnvironment(parse func() error) error {
+	envMu.RLock()
+	defer envMu.RUnlock()
+
+	return parse()
+}
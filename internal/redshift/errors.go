@@ -0,0 +1,129 @@
+package redshift
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrorKind groups the SQLSTATE codes redshift reports into the failures a
+// caller can act on.
+type ErrorKind int
+
+const (
+	ErrorKindUnknown ErrorKind = iota
+	ErrorKindDuplicateObject
+	ErrorKindInsufficientPrivilege
+	ErrorKindDependentObjects
+	ErrorKindInvalidPassword
+	ErrorKindUndefinedObject
+	// the provider's own login was refused, not the password of a resource
+	ErrorKindAuthenticationFailed
+)
+
+var errorKindsBySQLState = map[string]ErrorKind{
+	"42710": ErrorKindDuplicateObject, // duplicate_object
+	"42P06": ErrorKindDuplicateObject, // duplicate_schema
+	"42P07": ErrorKindDuplicateObject, // duplicate_table
+	"42723": ErrorKindDuplicateObject, // duplicate_function
+	"42501": ErrorKindInsufficientPrivilege,
+	"2BP01": ErrorKindDependentObjects,     // dependent_objects_still_exist
+	"28000": ErrorKindAuthenticationFailed, // invalid_authorization_specification
+	"28P01": ErrorKindAuthenticationFailed, // invalid_password
+	"42704": ErrorKindUndefinedObject,      // undefined_object
+	"42P01": ErrorKindUndefinedObject,      // undefined_table
+	"42883": ErrorKindUndefinedObject,      // undefined_function
+	"3F000": ErrorKindUndefinedObject,      // invalid_schema_name
+}
+
+// ClassifyError unwraps the *pgconn.PgError from an error returned by a
+// service and classifies it by SQLSTATE. Errors which did not come from the
+// server are ErrorKindUnknown with a nil *pgconn.PgError.
+func ClassifyError(err error) (ErrorKind, *pgconn.PgError) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return ErrorKindUnknown, nil
+	}
+
+	if kind, ok := errorKindsBySQLState[pgErr.Code]; ok {
+		return kind, pgErr
+	}
+
+	message := strings.ToLower(pgErr.Message)
+
+	// a password which breaks the rules is an invalid value or a syntax error
+	// like any other, only the message says it is about the password
+	switch pgErr.Code {
+	case "22023", // invalid_parameter_value
+		"42601": // syntax_error
+		if strings.Contains(message, "password") {
+			return ErrorKindInvalidPassword, pgErr
+		}
+	}
+
+	// redshift reports several of these as internal errors, only the message tells them apart
+	switch {
+	case strings.Contains(message, "already exists"):
+		return ErrorKindDuplicateObject, pgErr
+	case strings.Contains(message, "permission denied"):
+		return ErrorKindInsufficientPrivilege, pgErr
+	case strings.Contains(message, "depend on it"), strings.Contains(message, "cannot be dropped because"):
+		return ErrorKindDependentObjects, pgErr
+	case strings.Contains(message, "password must"):
+		return ErrorKindInvalidPassword, pgErr
+	case strings.Contains(message, "does not exist"):
+		return ErrorKindUndefinedObject, pgErr
+	}
+
+	return ErrorKindUnknown, pgErr
+}
+
+// IsRetryable reports whether err is a transient failure after which the whole
+// transaction can safely run again, because redshift rolled it back: a
+// serialization failure, a deadlock, or a connection lost to maintenance.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001", // serialization_failure
+			"40P01", // deadlock_detected
+			"57P01", // admin_shutdown
+			"57P02", // crash_shutdown
+			"57P03": // cannot_connect_now
+			return true
+		}
+
+		if strings.HasPrefix(pgErr.Code, "08") { // connection_exception
+			return true
+		}
+
+		// redshift raises error 1023 and lock conflicts as internal errors
+		message := strings.ToLower(pgErr.Message)
+		return strings.Contains(message, "serializable isolation violation") ||
+			strings.Contains(message, "concurrent transaction")
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		pgconn.SafeToRetry(err)
+}
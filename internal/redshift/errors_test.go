@@ -0,0 +1,156 @@
+package redshift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ClassifyError(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		err      error
+		expected ErrorKind
+	}
+	tests := map[string]testCase{
+		"not_from_server": {
+			err:      errors.New("dial tcp: connection refused"),
+			expected: ErrorKindUnknown,
+		},
+		"duplicate_object": {
+			err:      &pgconn.PgError{Code: "42710", Message: `role "bob" already exists`},
+			expected: ErrorKindDuplicateObject,
+		},
+		"insufficient_privilege": {
+			err:      &pgconn.PgError{Code: "42501", Message: "permission denied to create user"},
+			expected: ErrorKindInsufficientPrivilege,
+		},
+		"dependent_objects": {
+			err:      &pgconn.PgError{Code: "2BP01", Message: `cannot drop user "bob" because other objects depend on it`},
+			expected: ErrorKindDependentObjects,
+		},
+		"authentication_failed": {
+			err:      &pgconn.PgError{Code: "28P01", Message: `password authentication failed for user "admin"`},
+			expected: ErrorKindAuthenticationFailed,
+		},
+		"invalid_password": {
+			err:      &pgconn.PgError{Code: "22023", Message: "Password must contain a number."},
+			expected: ErrorKindInvalidPassword,
+		},
+		"invalid_password_syntax": {
+			err:      &pgconn.PgError{Code: "42601", Message: "Password must be at least 8 characters"},
+			expected: ErrorKindInvalidPassword,
+		},
+		"invalid_value_not_password": {
+			err:      &pgconn.PgError{Code: "22023", Message: "connection limit must be a positive number"},
+			expected: ErrorKindUnknown,
+		},
+		"undefined_object": {
+			err:      &pgconn.PgError{Code: "42704", Message: `group "admins" does not exist`},
+			expected: ErrorKindUndefinedObject,
+		},
+		"internal_error_by_message": {
+			err:      &pgconn.PgError{Code: "XX000", Message: `user "bob" cannot be dropped because the user owns some object`},
+			expected: ErrorKindDependentObjects,
+		},
+		"password_rules_by_message": {
+			err:      &pgconn.PgError{Code: "XX000", Message: "Password must contain a number."},
+			expected: ErrorKindInvalidPassword,
+		},
+		"unrecognised": {
+			err:      &pgconn.PgError{Code: "XX000", Message: "something else"},
+			expected: ErrorKindUnknown,
+		},
+		"wrapped": {
+			err:      fmt.Errorf("CreateUser: Failed to execute: %w", &pgconn.PgError{Code: "42710"}),
+			expected: ErrorKindDuplicateObject,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			kind, pgErr := ClassifyError(test.err)
+			assert.Equal(t, test.expected, kind)
+
+			if name == "not_from_server" {
+				assert.Nil(t, pgErr)
+			} else {
+				assert.NotNil(t, pgErr)
+			}
+		})
+	}
+}
+
+func Test_IsRetryable(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		err      error
+		expected bool
+	}
+	tests := map[string]testCase{
+		"serialization_failure": {
+			err:      &pgconn.PgError{Code: "40001"},
+			expected: true,
+		},
+		"serializable_isolation_violation": {
+			err:      fmt.Errorf("AlterGroup: Failed to commit: %w", &pgconn.PgError{Code: "XX000", Message: "1023 Serializable isolation violation on table - 123, transactions forming the cycle are: 1, 2"}),
+			expected: true,
+		},
+		"concurrent_transaction": {
+			err:      &pgconn.PgError{Code: "XX000", Message: "could not complete because of conflict with concurrent transaction"},
+			expected: true,
+		},
+		"admin_shutdown": {
+			err:      &pgconn.PgError{Code: "57P01"},
+			expected: true,
+		},
+		"connection_exception": {
+			err:      &pgconn.PgError{Code: "08006"},
+			expected: true,
+		},
+		"connection_reset": {
+			err:      fmt.Errorf("CreateUser: Failed to execute: %w", syscall.ECONNRESET),
+			expected: true,
+		},
+		"unexpected_eof": {
+			err:      io.ErrUnexpectedEOF,
+			expected: true,
+		},
+		"duplicate_object": {
+			err:      &pgconn.PgError{Code: "42710", Message: `user "bob" already exists`},
+			expected: false,
+		},
+		"authentication_failed": {
+			err:      &pgconn.PgError{Code: "28P01"},
+			expected: false,
+		},
+		"deadline_exceeded": {
+			err:      fmt.Errorf("FindUser: Unable to connect %w", context.DeadlineExceeded),
+			expected: false,
+		},
+		"other": {
+			err:      errors.New("Merge: error parsing template"),
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, IsRetryable(test.err))
+		})
+	}
+}
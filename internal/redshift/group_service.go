@@ -3,10 +3,10 @@ package redshift
 import (
 	"context"
 	"fmt"
+	"slices"
 	"terraform-provider-redshift/internal/helpers"
 	"time"
 
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -23,20 +23,16 @@ type Group struct {
 }
 
 type GroupService struct {
-	cfg     *pgx.ConnConfig
+	exec    Executor
 	ctx     context.Context
 	timeout time.Duration
 }
 
-func NewGroupService(ctx context.Context, cfg *pgx.ConnConfig) (*GroupService, error) {
-	timeout := cfg.ConnectTimeout
-	if cfg.ConnectTimeout <= 0 {
-		tflog.Info(ctx, "No timeout provided, using 5 minutes")
-		timeout = time.Minute * 5
-	}
+func NewGroupService(ctx context.Context, exec Executor) (*GroupService, error) {
+	timeout := operationTimeout(ctx)
 
 	return &GroupService{
-		cfg:     cfg,
+		exec:    exec,
 		ctx:     ctx,
 		timeout: timeout,
 	}, nil
@@ -71,30 +67,54 @@ func (s *GroupService) FindGroup(id string) (*Group, error) {
 	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
 	defer cancel()
 
-	conn, err := pgx.ConnectConfig(ctx, s.cfg)
-	if err != nil {
-		return nil, fmt.Errorf("FindGroup: Unable to connect %w", err)
-	}
+	var group *Group
+	err := s.exec.InTx(ctx, "FindGroup", func(tx Querier) error {
+		_, err := caseSensitiveSession(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("FindGroup: Failed to set enable_case_sensitive_identifier: %w", err)
+		}
 
-	opts := pgx.TxOptions{
-		BeginQuery: "SET enable_case_sensitive_identifier TO true",
-	}
-	tx, err := conn.BeginTx(ctx, opts)
-	if err != nil {
-		return nil, fmt.Errorf("FindGroup: Failed to begin transaction: %w", err)
-	}
+		group, err = buildGroup(sql, args, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("FindGroup: Failed to build group: %w", err)
+		}
+		if group == nil {
+			return fmt.Errorf("FindGroup: Could not find group with id '%s'", id)
+		}
 
-	group, err := buildGroup(sql, args, ctx, tx)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("FindGroup: Failed to build group: %w", err)
-	}
-	if group == nil {
-		return nil, fmt.Errorf("FindGroup: Could not find group with id '%s'", id)
+		return nil, err
 	}
 
-	err = tx.Commit(ctx)
+	return group, nil
+}
+
+// used where the group is referenced by name, like redshift_group_membership.
+func (s *GroupService) FindGroupByName(name string) (*Group, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	var group *Group
+	err := s.exec.InTx(ctx, "FindGroupByName", func(tx Querier) error {
+		_, err := caseSensitiveSession(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("FindGroupByName: Failed to set enable_case_sensitive_identifier: %w", err)
+		}
+
+		group, err = getGroupByName(name, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("FindGroupByName: Failed to build group: %w", err)
+		}
+		if group == nil {
+			return fmt.Errorf("FindGroupByName: Could not find group with name '%s'", name)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("FindGroup: Failed to commit: %w", err)
+		return nil, err
 	}
 
 	return group, nil
@@ -105,31 +125,21 @@ func (s *GroupService) DropGroup(name string) error {
 
 	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
 	defer cancel()
+	ctx = withAuditObject(ctx, name)
 
-	conn, err := pgx.ConnectConfig(ctx, s.cfg)
-	if err != nil {
-		return fmt.Errorf("DropGroup: Unable to connect %w", err)
-	}
-
-	opts := pgx.TxOptions{
-		BeginQuery: "SET enable_case_sensitive_identifier TO true",
-	}
-	tx, err := conn.BeginTx(ctx, opts)
-	if err != nil {
-		return fmt.Errorf("DropGroup: Failed to begin transaction: %w", err)
-	}
-
-	_, err = tx.Exec(ctx, sql)
-	if err != nil {
-		return fmt.Errorf("DropGroup: Failed to execute: %w", err)
-	}
+	return s.exec.InTx(ctx, "DropGroup", func(tx Querier) error {
+		_, err := caseSensitiveSession(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("DropGroup: Failed to set enable_case_sensitive_identifier: %w", err)
+		}
 
-	err = tx.Commit(ctx)
-	if err != nil {
-		return fmt.Errorf("DropGroup: Failed to commit: %w", err)
-	}
+		_, err = tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("DropGroup: Failed to execute: %w", err)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 type CreateGroupDDLParams struct {
@@ -138,60 +148,39 @@ type CreateGroupDDLParams struct {
 }
 
 func (s *GroupService) CreateGroup(args CreateGroupDDLParams) (*Group, error) {
-	t := `
-		CREATE GROUP {{.Name}}
-			{{if .Usernames}}{{$length := len .Usernames}}{{if gt $length 0 }}WITH USER {{(StringsJoin .Usernames ", ")}}{{end}}{{end}}
-	`
-	name := args.Name // save this unsanitized for lookup later
-	args.Name = pgx.Identifier{args.Name}.Sanitize()
-
-	if args.Usernames != nil && len(*args.Usernames) > 0 {
-		usernames := []string{}
-
-		for _, username := range *args.Usernames {
-			usernames = append(usernames, pgx.Identifier{username}.Sanitize())
-		}
-
-		args.Usernames = &usernames
-	}
-
 	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
 	defer cancel()
+	ctx = withAuditObject(ctx, args.Name)
 
-	conn, err := pgx.ConnectConfig(ctx, s.cfg)
-	if err != nil {
-		return nil, fmt.Errorf("CreateGroup: Unable to connect %w", err)
-	}
-
-	opts := pgx.TxOptions{
-		BeginQuery: "SET enable_case_sensitive_identifier TO true",
-	}
-	tx, err := conn.BeginTx(ctx, opts)
-	if err != nil {
-		return nil, fmt.Errorf("CreateGroup: Failed to begin transaction: %w", err)
-	}
+	var group *Group
+	err := s.exec.InTx(ctx, "CreateGroup", func(tx Querier) error {
+		caseSensitive, err := caseSensitiveSession(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("CreateGroup: Failed to set enable_case_sensitive_identifier: %w", err)
+		}
 
-	sql, err := helpers.Merge(t, args)
-	if err != nil {
-		return nil, fmt.Errorf("CreateGroup: Failed to merge template: %w", err)
-	}
+		sql, err := RenderCreateGroup(args, caseSensitive)
+		if err != nil {
+			return fmt.Errorf("CreateGroup: %w", err)
+		}
 
-	_, err = tx.Exec(ctx, sql)
-	if err != nil {
-		return nil, fmt.Errorf("CreateGroup: Failed to execute: %w", err)
-	}
+		_, err = tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("CreateGroup: Failed to execute: %w", err)
+		}
 
-	group, err := getGroupByName(name, ctx, tx)
-	if err != nil {
-		return nil, fmt.Errorf("CreateGroup: Failed to getGroupByName: %w", err)
-	}
-	if group == nil {
-		return nil, fmt.Errorf("CreateGroup: Could not find group with name '%s'", name)
-	}
+		group, err = getGroupByName(args.Name, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("CreateGroup: Failed to getGroupByName: %w", err)
+		}
+		if group == nil {
+			return fmt.Errorf("CreateGroup: Could not find group with name '%s'", args.Name)
+		}
 
-	err = tx.Commit(ctx)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("CreateGroup: Failed to commit: %w", err)
+		return nil, err
 	}
 
 	return group, nil
@@ -200,104 +189,234 @@ func (s *GroupService) CreateGroup(args CreateGroupDDLParams) (*Group, error) {
 type AlterGroupDDLParams struct {
 	Name     string
 	RenameTo *string
-	Add      *[]string
-	Drop     *[]string
+	// the complete membership, replaces Add and Drop when set
+	Usernames *[]string
+	Add       *[]string
+	Drop      *[]string
 }
 
 func (s *GroupService) AlterGroup(args AlterGroupDDLParams) error {
+	name := args.Name // save this unsanitized for lookup later
 	args.Name = pgx.Identifier{args.Name}.Sanitize()
 
+	if args.RenameTo != nil {
+		rn := pgx.Identifier{*args.RenameTo}.Sanitize()
+		args.RenameTo = &rn
+	}
+
 	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
 	defer cancel()
+	ctx = withAuditObject(ctx, name)
 
-	conn, err := pgx.ConnectConfig(ctx, s.cfg)
+	return s.exec.InTx(ctx, "AlterGroup", func(tx Querier) error {
+		// users are created without case sensitive identifiers, so their names
+		// are matched by the cluster setting rather than the group session's.
+		caseSensitive, err := caseSensitiveSession(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("AlterGroup: Failed to set enable_case_sensitive_identifier: %w", err)
+		}
+
+		// the membership is worked out again on every attempt, it may have
+		// changed since a failed one
+		ddl := args
+		if args.Usernames != nil || args.Add != nil || args.Drop != nil {
+			adds, drops, err := groupMembershipChanges(name, args, caseSensitive, ctx, tx)
+			if err != nil {
+				return fmt.Errorf("AlterGroup: Failed to groupMembershipChanges: %w", err)
+			}
+			ddl.Add = &adds
+			ddl.Drop = &drops
+		}
+
+		// redshift must perform add, drop and rename separately
+		if ddl.Add != nil && len(*ddl.Add) > 0 {
+			var adds []string
+			for _, add := range *ddl.Add {
+				username := pgx.Identifier{add}.Sanitize()
+				adds = append(adds, username)
+			}
+			ddl.Add = &adds
+
+			rename := `
+			ALTER GROUP {{.Name}}
+				ADD USER  {{(StringsJoin .Add ", ")}}
+			`
+			sql, err := helpers.Merge(rename, ddl)
+			if err != nil {
+				return fmt.Errorf("AlterGroup: failed to merge altergroup template: %w", err)
+			}
+
+			_, err = tx.Exec(ctx, sql)
+			if err != nil {
+				return fmt.Errorf("AlterGroup: failed to execute alter group: %w", err)
+			}
+		}
+
+		if ddl.Drop != nil && len(*ddl.Drop) > 0 {
+			var drops []string
+			for _, drop := range *ddl.Drop {
+				username := pgx.Identifier{drop}.Sanitize()
+				drops = append(drops, username)
+			}
+			ddl.Drop = &drops
+
+			rename := `
+			ALTER GROUP {{.Name}}
+				DROP USER  {{(StringsJoin .Drop ", ")}}
+			`
+			sql, err := helpers.Merge(rename, ddl)
+			if err != nil {
+				return fmt.Errorf("AlterGroup: failed to merge alter group template: %w", err)
+			}
+
+			_, err = tx.Exec(ctx, sql)
+			if err != nil {
+				return fmt.Errorf("AlterGroup: failed to execute alter group: %w", err)
+			}
+		}
+
+		if ddl.RenameTo != nil {
+			rename := `
+				ALTER GROUP {{.Name}} RENAME TO {{.RenameTo}}
+			`
+			sql, err := helpers.Merge(rename, ddl)
+			if err != nil {
+				return fmt.Errorf("AlterGroup: failed to merge rename template: %w", err)
+			}
+
+			_, err = tx.Exec(ctx, sql)
+			if err != nil {
+				return fmt.Errorf("AlterGroup: failed to execute rename: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+type pg_group_member struct {
+	Id       string `db:"usesysid"`
+	Username string `db:"usename"`
+}
+
+// works out the users to add and drop by user id, so a renamed user is still
+// the same member and names which only differ by case are not churned.
+func groupMembershipChanges(groupName string, args AlterGroupDDLParams, caseSensitive bool, ctx context.Context, tx Querier) ([]string, []string, error) {
+	sql := `
+		SELECT pu.usesysid::varchar AS usesysid,
+			   pu.usename
+		  FROM pg_user pu
+	`
+	rows, err := tx.Query(ctx, sql)
 	if err != nil {
-		return fmt.Errorf("AlterGroup: Unable to connect %w", err)
+		return nil, nil, fmt.Errorf("groupMembershipChanges: Failed users query execute: %w", err)
 	}
 
-	opts := pgx.TxOptions{
-		BeginQuery: "SET enable_case_sensitive_identifier TO true",
+	users, err := pgx.CollectRows(rows, pgx.RowToStructByName[pg_group_member])
+	if err != nil {
+		return nil, nil, fmt.Errorf("groupMembershipChanges: Failed to collect users: %w", err)
 	}
-	tx, err := conn.BeginTx(ctx, opts)
+
+	sql = `
+		SELECT pu.usesysid::varchar AS usesysid,
+			   pu.usename
+		  FROM pg_user pu
+			   JOIN pg_group pg
+				 ON pu.usesysid = ANY ( pg.grolist )
+		 WHERE pg.groname = @GroupName
+	`
+	rows, err = tx.Query(ctx, sql, pgx.NamedArgs{"GroupName": groupName})
 	if err != nil {
-		return fmt.Errorf("AlterGroup: Failed to begin transaction: %w", err)
+		return nil, nil, fmt.Errorf("groupMembershipChanges: Failed members query execute: %w", err)
 	}
 
-	// redshift must perform add, drop and rename separately
-	if args.Add != nil && len(*args.Add) > 0 {
-		var adds []string
-		for _, add := range *args.Add {
-			username := pgx.Identifier{add}.Sanitize()
-			adds = append(adds, username)
-		}
-		args.Add = &adds
+	members, err := pgx.CollectRows(rows, pgx.RowToStructByName[pg_group_member])
+	if err != nil {
+		return nil, nil, fmt.Errorf("groupMembershipChanges: Failed to collect members: %w", err)
+	}
 
-		rename := `
-		ALTER GROUP {{.Name}}
-			ADD USER  {{(StringsJoin .Add ", ")}}
-		`
-		sql, err := helpers.Merge(rename, args)
-		if err != nil {
-			return fmt.Errorf("AlterGroup: failed to merge altergroup template: %w", err)
-		}
+	ids := map[string]string{}
+	for _, user := range users {
+		ids[user.Username] = user.Id
+	}
 
-		_, err = tx.Exec(ctx, sql)
-		if err != nil {
-			return fmt.Errorf("AlterGroup: failed to execute alter group: %w", err)
-		}
+	memberIds := map[string]string{}
+	for _, member := range members {
+		memberIds[member.Id] = member.Username
 	}
 
-	if args.Drop != nil && len(*args.Drop) > 0 {
-		var drops []string
-		for _, drop := range *args.Drop {
-			username := pgx.Identifier{drop}.Sanitize()
-			drops = append(drops, username)
-		}
-		args.Drop = &drops
+	var adds, drops []string
 
-		rename := `
-		ALTER GROUP {{.Name}}
-			DROP USER  {{(StringsJoin .Drop ", ")}}
-		`
-		sql, err := helpers.Merge(rename, args)
-		if err != nil {
-			return fmt.Errorf("AlterGroup: failed to merge alter group template: %w", err)
-		}
+	add := func(names []string) map[string]bool {
+		wanted := map[string]bool{}
+		for _, name := range names {
+			name = helpers.NormalizeIdentifier(name, caseSensitive)
 
-		_, err = tx.Exec(ctx, sql)
-		if err != nil {
-			return fmt.Errorf("AlterGroup: failed to execute alter group: %w", err)
+			id, ok := ids[name]
+			if !ok {
+				// let redshift report the unknown user
+				adds = append(adds, name)
+				continue
+			}
+
+			wanted[id] = true
+			if _, ok := memberIds[id]; !ok && !slices.Contains(adds, name) {
+				adds = append(adds, name)
+			}
 		}
+		return wanted
 	}
 
-	if args.RenameTo != nil {
-		rn := pgx.Identifier{*args.RenameTo}.Sanitize()
-		args.RenameTo = &rn
+	if args.Usernames != nil {
+		wanted := add(*args.Usernames)
 
-		rename := `
-			ALTER GROUP {{.Name}} RENAME TO {{.RenameTo}}
-		`
-		sql, err := helpers.Merge(rename, args)
-		if err != nil {
-			return fmt.Errorf("AlterGroup: failed to merge rename template: %w", err)
+		for _, member := range members {
+			if !wanted[member.Id] {
+				drops = append(drops, member.Username)
+			}
 		}
 
-		_, err = tx.Exec(ctx, sql)
-		if err != nil {
-			return fmt.Errorf("AlterGroup: failed to execute rename: %w", err)
+		return adds, drops, nil
+	}
+
+	wanted := map[string]bool{}
+	if args.Add != nil {
+		wanted = add(*args.Add)
+	}
+
+	if args.Drop != nil {
+		for _, name := range *args.Drop {
+			// a user who has gone or already left needs no drop, nor does one
+			// added again under a name which folds to theirs
+			id := ids[helpers.NormalizeIdentifier(name, caseSensitive)]
+			member, ok := memberIds[id]
+			if ok && !wanted[id] && !slices.Contains(drops, member) {
+				drops = append(drops, member)
+			}
 		}
 	}
 
-	err = tx.Commit(ctx)
+	return adds, drops, nil
+}
+
+// reads the cluster setting and then turns on case sensitive identifiers for
+// the rest of the transaction, group names keep their case.
+func caseSensitiveSession(ctx context.Context, tx Querier) (bool, error) {
+	caseSensitive, err := caseSensitiveIdentifiers(ctx, tx)
+	if err != nil {
+		return false, fmt.Errorf("caseSensitiveSession: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, "SET LOCAL enable_case_sensitive_identifier TO true")
 	if err != nil {
-		return fmt.Errorf("AlterGroup: Failed to commit: %w", err)
+		return false, fmt.Errorf("caseSensitiveSession: Failed to execute: %w", err)
 	}
 
-	return nil
+	return caseSensitive, nil
 }
 
-// hidden from outside the package, expect that callers use the ById variant.
-func getGroupByName(name string, ctx context.Context, tx pgx.Tx) (*Group, error) {
+// hidden from outside the package, callers use FindGroup or FindGroupByName.
+func getGroupByName(name string, ctx context.Context, tx Querier) (*Group, error) {
 	// SQL return a group even if no users are in the group
 	sql := `
 		WITH groups_no_users
@@ -326,7 +445,7 @@ func getGroupByName(name string, ctx context.Context, tx pgx.Tx) (*Group, error)
 	return buildGroup(sql, args, ctx, tx)
 }
 
-func buildGroup(sql string, args pgx.NamedArgs, ctx context.Context, tx pgx.Tx) (*Group, error) {
+func buildGroup(sql string, args pgx.NamedArgs, ctx context.Context, tx Querier) (*Group, error) {
 	rows, err := tx.Query(ctx, sql, args)
 	if err != nil {
 		return nil, fmt.Errorf("buildGroup: Failed query execute: %w", err)
@@ -0,0 +1,161 @@
+package redshift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	caseSensitiveSettingSQL = "SELECT current_setting('enable_case_sensitive_identifier')"
+	caseSensitiveSessionSQL = "SET LOCAL enable_case_sensitive_identifier TO true"
+	groupByNameSQL          = `
+		WITH groups_no_users
+			 AS (SELECT groname,
+					    grosysid,
+					    NULL AS usename
+				   FROM pg_group),
+			groups_with_users
+			AS (SELECT pg.grosysid,
+					   pu.usename
+				  FROM pg_user pu
+					   LEFT JOIN pg_group pg
+							  ON pu.usesysid = ANY ( pg.grolist ))
+		SELECT t1.groname,
+			   t1.grosysid,
+			   t2.usename
+		  FROM groups_no_users t1
+			   LEFT JOIN groups_with_users t2
+					  ON t1.grosysid = t2.grosysid
+		 WHERE t1.groname = @GroupName
+		 ORDER BY t1.groname,
+				  t2.usename
+	`
+	usersSQL = `
+		SELECT pu.usesysid::varchar AS usesysid,
+			   pu.usename
+		  FROM pg_user pu
+	`
+	groupMembersSQL = `
+		SELECT pu.usesysid::varchar AS usesysid,
+			   pu.usename
+		  FROM pg_user pu
+			   JOIN pg_group pg
+				 ON pu.usesysid = ANY ( pg.grolist )
+		 WHERE pg.groname = @GroupName
+	`
+)
+
+func expectCaseSensitiveSession(mock pgxmock.PgxConnIface, setting string) {
+	mock.ExpectQuery(caseSensitiveSettingSQL).
+		WillReturnRows(pgxmock.NewRows([]string{"current_setting"}).AddRow(setting))
+	mock.ExpectExec(caseSensitiveSessionSQL).
+		WillReturnResult(pgxmock.NewResult("SET", 0))
+}
+
+func Test_GroupService(t *testing.T) {
+	t.Parallel()
+
+	admins := "Admins"
+	usernames := []string{"Alice", "bob"}
+
+	type testCase struct {
+		run    func(svc *GroupService) error
+		expect func(mock pgxmock.PgxConnIface)
+	}
+	tests := map[string]testCase{
+		"create_with_users": {
+			run: func(svc *GroupService) error {
+				group, err := svc.CreateGroup(CreateGroupDDLParams{Name: "Admins", Usernames: &usernames})
+				if err == nil {
+					assert.Equal(t, []string{"alice", "bob"}, *group.Users)
+				}
+				return err
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				expectCaseSensitiveSession(mock, "off")
+				// users are matched case insensitively unless the cluster says otherwise
+				mock.ExpectExec(`CREATE GROUP "Admins" WITH USER "alice", "bob"`).
+					WillReturnResult(pgxmock.NewResult("CREATE GROUP", 0))
+				mock.ExpectQuery(groupByNameSQL).
+					WithArgs(pgx.NamedArgs{"GroupName": "Admins"}).
+					WillReturnRows(pgxmock.NewRows([]string{"groname", "grosysid", "usename"}).
+						AddRow("Admins", "200", &[]string{"alice"}[0]).
+						AddRow("Admins", "200", &[]string{"bob"}[0]))
+				mock.ExpectCommit()
+			},
+		},
+		"alter_membership_before_rename": {
+			run: func(svc *GroupService) error {
+				return svc.AlterGroup(AlterGroupDDLParams{Name: "admins", RenameTo: &admins, Usernames: &usernames})
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				expectCaseSensitiveSession(mock, "off")
+				mock.ExpectQuery(usersSQL).
+					WillReturnRows(pgxmock.NewRows([]string{"usesysid", "usename"}).
+						AddRow("100", "alice").
+						AddRow("101", "bob").
+						AddRow("102", "carol"))
+				mock.ExpectQuery(groupMembersSQL).
+					WithArgs(pgx.NamedArgs{"GroupName": "admins"}).
+					WillReturnRows(pgxmock.NewRows([]string{"usesysid", "usename"}).
+						AddRow("101", "bob").
+						AddRow("102", "carol"))
+				// the group is still known by its old name until the end
+				mock.ExpectExec(`ALTER GROUP "admins" ADD USER "alice"`).
+					WillReturnResult(pgxmock.NewResult("ALTER GROUP", 0))
+				mock.ExpectExec(`ALTER GROUP "admins" DROP USER "carol"`).
+					WillReturnResult(pgxmock.NewResult("ALTER GROUP", 0))
+				mock.ExpectExec(`ALTER GROUP "admins" RENAME TO "Admins"`).
+					WillReturnResult(pgxmock.NewResult("ALTER GROUP", 0))
+				mock.ExpectCommit()
+			},
+		},
+		"alter_rename_only": {
+			run: func(svc *GroupService) error {
+				return svc.AlterGroup(AlterGroupDDLParams{Name: "admins", RenameTo: &admins})
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				expectCaseSensitiveSession(mock, "off")
+				mock.ExpectExec(`ALTER GROUP "admins" RENAME TO "Admins"`).
+					WillReturnResult(pgxmock.NewResult("ALTER GROUP", 0))
+				mock.ExpectCommit()
+			},
+		},
+		"drop": {
+			run: func(svc *GroupService) error {
+				return svc.DropGroup("Admins")
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				expectCaseSensitiveSession(mock, "off")
+				mock.ExpectExec(`DROP GROUP "Admins"`).
+					WillReturnResult(pgxmock.NewResult("DROP GROUP", 0))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			executor, mock := newMockExecutor(t)
+			test.expect(mock)
+
+			svc, err := NewGroupService(context.Background(), executor)
+			if err != nil {
+				t.Fatalf("failed to create service: %s", err)
+			}
+
+			assert.NoError(t, test.run(svc))
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package redshift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"terraform-provider-redshift/internal/helpers"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type svv_identity_providers struct {
+	Id         string  `db:"uid"`
+	Name       string  `db:"name"`
+	Type       string  `db:"type"`
+	Namespace  string  `db:"namespc"`
+	Parameters *string `db:"params"`
+	Enabled    bool    `db:"enabled"`
+}
+
+// The PARAMETERS json of an azure identity provider.
+type IdentityProviderParameters struct {
+	Issuer       string   `json:"issuer"`
+	ClientId     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	Audiences    []string `json:"audience"`
+}
+
+type IdentityProvider struct {
+	svv_identity_providers
+	// the client secret is never returned by the catalog
+	Params IdentityProviderParameters
+}
+
+type IdentityProviderService struct {
+	exec    Executor
+	ctx     context.Context
+	timeout time.Duration
+}
+
+func NewIdentityProviderService(ctx context.Context, exec Executor) (*IdentityProviderService, error) {
+	timeout := operationTimeout(ctx)
+
+	return &IdentityProviderService{
+		exec:    exec,
+		ctx:     ctx,
+		timeout: timeout,
+	}, nil
+}
+
+func (s *IdentityProviderService) FindIdentityProvider(id string) (*IdentityProvider, error) {
+	sql := `
+	SELECT svv.uid::varchar,
+		   svv.name,
+		   svv.type,
+		   svv.namespc,
+		   svv.params,
+		   svv.enabled
+	  FROM svv_identity_providers svv
+	 WHERE svv.uid = @IdentityProviderId
+	`
+	args := pgx.NamedArgs{"IdentityProviderId": id}
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	var idp *IdentityProvider
+	err := s.exec.InTx(ctx, "FindIdentityProvider", func(tx Querier) error {
+		var err error
+		idp, err = buildIdentityProvider(sql, args, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("FindIdentityProvider: Failed to build identity provider: %w", err)
+		}
+
+		if idp == nil {
+			return fmt.Errorf("FindIdentityProvider: Could not find identity provider with id '%s'", id)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return idp, nil
+}
+
+func (s *IdentityProviderService) DropIdentityProvider(name string) error {
+	sql := fmt.Sprintf("DROP IDENTITY PROVIDER %s", pgx.Identifier{name}.Sanitize())
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, name)
+
+	return s.exec.InTx(ctx, "DropIdentityProvider", func(tx Querier) error {
+		_, err := tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("DropIdentityProvider: Failed to execute: %w", err)
+		}
+
+		return nil
+	})
+}
+
+type CreateIdentityProviderDDLParams struct {
+	Name       string
+	Type       string
+	Namespace  string
+	Parameters IdentityProviderParameters
+	Enabled    bool
+}
+
+func (s *IdentityProviderService) CreateIdentityProvider(args CreateIdentityProviderDDLParams) (*IdentityProvider, error) {
+	t := `
+		CREATE IDENTITY PROVIDER {{.Name}} TYPE {{.Type}}
+			NAMESPACE '{{.Namespace}}'
+			PARAMETERS '{{.Parameters}}'
+	`
+	name := args.Name // save this unsanitized for lookup later
+
+	parameters, err := json.Marshal(args.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("CreateIdentityProvider: Failed to marshal parameters: %w", err)
+	}
+
+	params := struct {
+		Name       string
+		Type       string
+		Namespace  string
+		Parameters string
+	}{
+		Name:       pgx.Identifier{args.Name}.Sanitize(),
+		Type:       args.Type,
+		Namespace:  helpers.EscapeLiteral(args.Namespace),
+		Parameters: helpers.EscapeLiteral(string(parameters)),
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, name)
+
+	var idp *IdentityProvider
+	err = s.exec.InTx(ctx, "CreateIdentityProvider", func(tx Querier) error {
+		sql, err := helpers.Merge(t, params)
+		if err != nil {
+			return fmt.Errorf("CreateIdentityProvider: Failed to merge template: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("CreateIdentityProvider: Failed to execute: %w", err)
+		}
+
+		// a new identity provider is always enabled
+		if !args.Enabled {
+			_, err = tx.Exec(ctx, fmt.Sprintf("ALTER IDENTITY PROVIDER %s DISABLE", params.Name))
+			if err != nil {
+				return fmt.Errorf("CreateIdentityProvider: Failed to execute disable: %w", err)
+			}
+		}
+
+		idp, err = getIdentityProviderByName(name, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("CreateIdentityProvider: Failed to getIdentityProviderByName: %w", err)
+		}
+		if idp == nil {
+			return fmt.Errorf("CreateIdentityProvider: Could not find identity provider with name '%s'", name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return idp, nil
+}
+
+type AlterIdentityProviderDDLParams struct {
+	Name       string
+	Namespace  *string
+	Parameters *IdentityProviderParameters
+	Enabled    *bool
+}
+
+func (s *IdentityProviderService) AlterIdentityProvider(args AlterIdentityProviderDDLParams) error {
+	name := pgx.Identifier{args.Name}.Sanitize()
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, args.Name)
+
+	return s.exec.InTx(ctx, "AlterIdentityProvider", func(tx Querier) error {
+		var err error
+		// each clause is altered with its own statement
+		if args.Namespace != nil {
+			sql := fmt.Sprintf("ALTER IDENTITY PROVIDER %s NAMESPACE '%s'", name, helpers.EscapeLiteral(*args.Namespace))
+
+			_, err = tx.Exec(ctx, sql)
+			if err != nil {
+				return fmt.Errorf("AlterIdentityProvider: failed to execute namespace: %w", err)
+			}
+		}
+
+		if args.Parameters != nil {
+			parameters, err := json.Marshal(args.Parameters)
+			if err != nil {
+				return fmt.Errorf("AlterIdentityProvider: failed to marshal parameters: %w", err)
+			}
+
+			sql := fmt.Sprintf("ALTER IDENTITY PROVIDER %s PARAMETERS '%s'", name, helpers.EscapeLiteral(string(parameters)))
+
+			_, err = tx.Exec(ctx, sql)
+			if err != nil {
+				return fmt.Errorf("AlterIdentityProvider: failed to execute parameters: %w", err)
+			}
+		}
+
+		if args.Enabled != nil {
+			state := "DISABLE"
+			if *args.Enabled {
+				state = "ENABLE"
+			}
+
+			_, err = tx.Exec(ctx, fmt.Sprintf("ALTER IDENTITY PROVIDER %s %s", name, state))
+			if err != nil {
+				return fmt.Errorf("AlterIdentityProvider: failed to execute %s: %w", state, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// hidden from outside the package, expect that callers use the ById variant.
+func getIdentityProviderByName(name string, ctx context.Context, tx Querier) (*IdentityProvider, error) {
+	sql := `
+	SELECT svv.uid::varchar,
+		   svv.name,
+		   svv.type,
+		   svv.namespc,
+		   svv.params,
+		   svv.enabled
+	  FROM svv_identity_providers svv
+	 WHERE svv.name = @IdentityProviderName
+	`
+	args := pgx.NamedArgs{"IdentityProviderName": name}
+
+	return buildIdentityProvider(sql, args, ctx, tx)
+}
+
+func buildIdentityProvider(sql string, args pgx.NamedArgs, ctx context.Context, tx Querier) (*IdentityProvider, error) {
+	rows, err := tx.Query(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("buildIdentityProvider: Failed query execute: %w", err)
+	}
+
+	svv_identity_providers, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[svv_identity_providers])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("buildIdentityProvider: Failed to collect row: %w", err)
+	}
+
+	var params IdentityProviderParameters
+	if svv_identity_providers.Parameters != nil && *svv_identity_providers.Parameters != "" {
+		err = json.Unmarshal([]byte(*svv_identity_providers.Parameters), &params)
+		if err != nil {
+			return nil, fmt.Errorf("buildIdentityProvider: Failed to parse parameters: %w", err)
+		}
+	}
+
+	idp := IdentityProvider{
+		svv_identity_providers: svv_identity_providers,
+		Params:                 params,
+	}
+
+	return &idp, nil
+}
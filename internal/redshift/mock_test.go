@@ -0,0 +1,56 @@
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+// mockExecutor runs each transaction on a pgxmock connection, so a test
+// expects the exact statements and arguments a service sends, in order.
+type mockExecutor struct {
+	mock pgxmock.PgxConnIface
+}
+
+func newMockExecutor(t *testing.T) (*mockExecutor, pgxmock.PgxConnIface) {
+	t.Helper()
+
+	mock, err := pgxmock.NewConn(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("failed to create mock: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return &mockExecutor{mock: mock}, mock
+}
+
+func (e *mockExecutor) InTx(ctx context.Context, name string, fn func(tx Querier) error) error {
+	tx, err := e.mock.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: Failed to begin transaction: %w", name, err)
+	}
+
+	err = fn(tx)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: Failed to commit: %w", name, err)
+	}
+
+	return nil
+}
+
+// the mock stands for a single connection, whichever the database
+func (e *mockExecutor) ForDatabase(database string) Executor {
+	return e
+}
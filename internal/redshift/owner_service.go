@@ -0,0 +1,328 @@
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-redshift/internal/helpers"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type pg_object_owner struct {
+	Name  string `db:"name"`
+	Owner string `db:"owner"`
+}
+
+type pg_schema_object struct {
+	Name      string  `db:"name"`
+	Arguments *string `db:"arguments"`
+	Kind      string  `db:"kind"`
+	Owner     string  `db:"owner"`
+}
+
+type Owner struct {
+	pg_object_owner
+	// only populated when the objects of a schema were requested, true when
+	// every table, view, function and procedure in the schema has the same owner.
+	AllObjectsOwned bool
+}
+
+type OwnerService struct {
+	exec    Executor
+	ctx     context.Context
+	timeout time.Duration
+}
+
+func NewOwnerService(ctx context.Context, exec Executor) (*OwnerService, error) {
+	timeout := operationTimeout(ctx)
+
+	return &OwnerService{
+		exec:    exec,
+		ctx:     ctx,
+		timeout: timeout,
+	}, nil
+}
+
+type OwnerDDLParams struct {
+	// one of schema, table, view, function, procedure or database
+	ObjectType string
+	SchemaName string
+	ObjectName string
+	// argument types of a function or procedure, e.g. `integer, varchar`
+	Arguments      string
+	Owner          string
+	IncludeObjects bool
+}
+
+func (s *OwnerService) FindOwner(args OwnerDDLParams) (*Owner, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	var owner *Owner
+	err := s.exec.InTx(ctx, "FindOwner", func(tx Querier) error {
+		var err error
+		owner, err = getOwner(args, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("FindOwner: Failed to build owner: %w", err)
+		}
+
+		if owner == nil {
+			return fmt.Errorf("FindOwner: Could not find %s '%s'", args.ObjectType, args.ObjectName)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return owner, nil
+}
+
+func (s *OwnerService) AlterOwner(args OwnerDDLParams) (*Owner, error) {
+	t := `
+		ALTER {{.Keyword}} {{.Object}} OWNER TO {{.Owner}}
+	`
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, ownedObject(args))
+
+	var owner *Owner
+	err := s.exec.InTx(ctx, "AlterOwner", func(tx Querier) error {
+		sql, err := helpers.Merge(t, ownerTemplateParams(args.ObjectType, args.SchemaName, args.ObjectName, args.Arguments, args.Owner))
+		if err != nil {
+			return fmt.Errorf("AlterOwner: Failed to merge template: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("AlterOwner: Failed to execute: %w", err)
+		}
+
+		// reassign every object in the schema which is not already owned
+		if args.ObjectType == "schema" && args.IncludeObjects {
+			caseSensitive, err := caseSensitiveIdentifiers(ctx, tx)
+			if err != nil {
+				return fmt.Errorf("AlterOwner: %w", err)
+			}
+
+			objects, err := getSchemaObjects(args.ObjectName, ctx, tx)
+			if err != nil {
+				return fmt.Errorf("AlterOwner: Failed to getSchemaObjects: %w", err)
+			}
+
+			// the catalog holds the owner as redshift folded the name
+			for _, object := range objects {
+				if object.Owner == helpers.NormalizeIdentifier(args.Owner, caseSensitive) {
+					continue
+				}
+
+				arguments := ""
+				if object.Arguments != nil {
+					arguments = *object.Arguments
+				}
+
+				sql, err := helpers.Merge(t, ownerTemplateParams(object.Kind, args.ObjectName, object.Name, arguments, args.Owner))
+				if err != nil {
+					return fmt.Errorf("AlterOwner: Failed to merge object template: %w", err)
+				}
+
+				_, err = tx.Exec(ctx, sql)
+				if err != nil {
+					return fmt.Errorf("AlterOwner: Failed to execute object: %w", err)
+				}
+			}
+		}
+
+		owner, err = getOwner(args, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("AlterOwner: Failed to getOwner: %w", err)
+		}
+		if owner == nil {
+			return fmt.Errorf("AlterOwner: Could not find %s '%s'", args.ObjectType, args.ObjectName)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return owner, nil
+}
+
+type ownerParams struct {
+	Keyword string
+	Object  string
+	Owner   string
+}
+
+func ownerTemplateParams(objectType string, schemaName string, objectName string, arguments string, owner string) ownerParams {
+	params := ownerParams{
+		Keyword: strings.ToUpper(objectType),
+		Owner:   pgx.Identifier{owner}.Sanitize(),
+	}
+
+	switch objectType {
+	case "schema", "database":
+		params.Object = pgx.Identifier{objectName}.Sanitize()
+	case "view":
+		// redshift changes the owner of a view through ALTER TABLE
+		params.Keyword = "TABLE"
+		params.Object = pgx.Identifier{schemaName, objectName}.Sanitize()
+	case "function", "procedure":
+		params.Object = fmt.Sprintf("%s(%s)", pgx.Identifier{schemaName, objectName}.Sanitize(), arguments)
+	default:
+		params.Object = pgx.Identifier{schemaName, objectName}.Sanitize()
+	}
+
+	return params
+}
+
+// names the object for the audit, as ownerTemplateParams does unquoted.
+func ownedObject(args OwnerDDLParams) string {
+	switch args.ObjectType {
+	case "schema", "database":
+		return args.ObjectName
+	case "function", "procedure":
+		return fmt.Sprintf("%s(%s)", auditName(args.SchemaName, args.ObjectName), args.Arguments)
+	default:
+		return auditName(args.SchemaName, args.ObjectName)
+	}
+}
+
+func getOwner(args OwnerDDLParams, ctx context.Context, tx Querier) (*Owner, error) {
+	var sql string
+	namedArgs := pgx.NamedArgs{
+		"SchemaName": args.SchemaName,
+		"ObjectName": args.ObjectName,
+		"Arguments":  args.Arguments,
+	}
+
+	switch args.ObjectType {
+	case "schema":
+		sql = `
+		SELECT nsp.nspname AS name,
+			   pg_get_userbyid(nsp.nspowner) AS owner
+		  FROM pg_namespace nsp
+		 WHERE nsp.nspname = @ObjectName
+		`
+	case "database":
+		sql = `
+		SELECT db.datname AS name,
+			   pg_get_userbyid(db.datdba) AS owner
+		  FROM pg_database db
+		 WHERE db.datname = @ObjectName
+		`
+	case "table", "view":
+		namedArgs["RelKind"] = "r"
+		if args.ObjectType == "view" {
+			namedArgs["RelKind"] = "v"
+		}
+
+		sql = `
+		SELECT cls.relname AS name,
+			   pg_get_userbyid(cls.relowner) AS owner
+		  FROM pg_class cls
+			   JOIN pg_namespace nsp
+				 ON nsp.oid = cls.relnamespace
+		 WHERE nsp.nspname = @SchemaName
+		   AND cls.relname = @ObjectName
+		   AND cls.relkind = @RelKind
+		`
+	case "function", "procedure":
+		namedArgs["ProKind"] = "f"
+		if args.ObjectType == "procedure" {
+			namedArgs["ProKind"] = "p"
+		}
+
+		sql = `
+		SELECT pro.proname AS name,
+			   pg_get_userbyid(pro.proowner) AS owner
+		  FROM pg_proc_info pro
+			   JOIN pg_namespace nsp
+				 ON nsp.oid = pro.pronamespace
+		 WHERE nsp.nspname = @SchemaName
+		   AND pro.proname = @ObjectName
+		   AND oidvectortypes(pro.proargtypes) = @Arguments
+		   AND pro.prokind = @ProKind
+		`
+	default:
+		return nil, fmt.Errorf("getOwner: Unsupported object type '%s'", args.ObjectType)
+	}
+
+	rows, err := tx.Query(ctx, sql, namedArgs)
+	if err != nil {
+		return nil, fmt.Errorf("getOwner: Failed query execute: %w", err)
+	}
+
+	pg_object_owner, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[pg_object_owner])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("getOwner: Failed to collect row: %w", err)
+	}
+
+	owner := Owner{
+		pg_object_owner: pg_object_owner,
+	}
+
+	if args.ObjectType == "schema" && args.IncludeObjects {
+		objects, err := getSchemaObjects(args.ObjectName, ctx, tx)
+		if err != nil {
+			return nil, fmt.Errorf("getOwner: Failed to getSchemaObjects: %w", err)
+		}
+
+		owner.AllObjectsOwned = true
+		for _, object := range objects {
+			if object.Owner != owner.Owner {
+				owner.AllObjectsOwned = false
+				break
+			}
+		}
+	}
+
+	return &owner, nil
+}
+
+// the tables, views, functions and procedures in a schema with their owners.
+func getSchemaObjects(schemaName string, ctx context.Context, tx Querier) ([]pg_schema_object, error) {
+	sql := `
+	SELECT cls.relname AS name,
+		   NULL::varchar AS arguments,
+		   CASE cls.relkind WHEN 'v' THEN 'view' ELSE 'table' END AS kind,
+		   pg_get_userbyid(cls.relowner) AS owner
+	  FROM pg_class cls
+		   JOIN pg_namespace nsp
+			 ON nsp.oid = cls.relnamespace
+	 WHERE nsp.nspname = @SchemaName
+	   AND cls.relkind IN ('r', 'v')
+	 UNION ALL
+	SELECT pro.proname AS name,
+		   oidvectortypes(pro.proargtypes) AS arguments,
+		   CASE pro.prokind WHEN 'p' THEN 'procedure' ELSE 'function' END AS kind,
+		   pg_get_userbyid(pro.proowner) AS owner
+	  FROM pg_proc_info pro
+		   JOIN pg_namespace nsp
+			 ON nsp.oid = pro.pronamespace
+	 WHERE nsp.nspname = @SchemaName
+	`
+	args := pgx.NamedArgs{"SchemaName": schemaName}
+
+	rows, err := tx.Query(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("getSchemaObjects: Failed query execute: %w", err)
+	}
+
+	objects, err := pgx.CollectRows(rows, pgx.RowToStructByName[pg_schema_object])
+	if err != nil {
+		return nil, fmt.Errorf("getSchemaObjects: Failed to collect rows: %w", err)
+	}
+
+	return objects, nil
+}
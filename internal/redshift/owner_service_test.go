@@ -0,0 +1,82 @@
+package redshift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	schemaOwnerSQL = `
+		SELECT nsp.nspname AS name,
+			   pg_get_userbyid(nsp.nspowner) AS owner
+		  FROM pg_namespace nsp
+		 WHERE nsp.nspname = @ObjectName
+	`
+	schemaObjectsSQL = `
+	SELECT cls.relname AS name,
+		   NULL::varchar AS arguments,
+		   CASE cls.relkind WHEN 'v' THEN 'view' ELSE 'table' END AS kind,
+		   pg_get_userbyid(cls.relowner) AS owner
+	  FROM pg_class cls
+		   JOIN pg_namespace nsp
+			 ON nsp.oid = cls.relnamespace
+	 WHERE nsp.nspname = @SchemaName
+	   AND cls.relkind IN ('r', 'v')
+	 UNION ALL
+	SELECT pro.proname AS name,
+		   oidvectortypes(pro.proargtypes) AS arguments,
+		   CASE pro.prokind WHEN 'p' THEN 'procedure' ELSE 'function' END AS kind,
+		   pg_get_userbyid(pro.proowner) AS owner
+	  FROM pg_proc_info pro
+		   JOIN pg_namespace nsp
+			 ON nsp.oid = pro.pronamespace
+	 WHERE nsp.nspname = @SchemaName
+	`
+)
+
+func Test_OwnerService_AlterOwner_folded(t *testing.T) {
+	t.Parallel()
+
+	exec, mock := newMockExecutor(t)
+
+	schemaObjects := func() *pgxmock.Rows {
+		return pgxmock.NewRows([]string{"name", "arguments", "kind", "owner"}).
+			AddRow("orders", nil, "table", "admin").
+			AddRow("refunds", nil, "table", "bob")
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`ALTER SCHEMA "sales" OWNER TO "Admin"`).
+		WillReturnResult(pgxmock.NewResult("ALTER SCHEMA", 0))
+	mock.ExpectQuery(caseSensitiveSettingSQL).
+		WillReturnRows(pgxmock.NewRows([]string{"current_setting"}).AddRow("off"))
+	mock.ExpectQuery(schemaObjectsSQL).
+		WithArgs(pgx.NamedArgs{"SchemaName": "sales"}).
+		WillReturnRows(schemaObjects())
+	// orders is already owned by the folded name, only refunds is reassigned
+	mock.ExpectExec(`ALTER TABLE "sales"."refunds" OWNER TO "Admin"`).
+		WillReturnResult(pgxmock.NewResult("ALTER TABLE", 0))
+	mock.ExpectQuery(schemaOwnerSQL).
+		WithArgs(pgx.NamedArgs{"SchemaName": "", "ObjectName": "sales", "Arguments": ""}).
+		WillReturnRows(pgxmock.NewRows([]string{"name", "owner"}).AddRow("sales", "admin"))
+	mock.ExpectQuery(schemaObjectsSQL).
+		WithArgs(pgx.NamedArgs{"SchemaName": "sales"}).
+		WillReturnRows(schemaObjects())
+	mock.ExpectCommit()
+
+	svc, err := NewOwnerService(context.Background(), exec)
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	owner, err := svc.AlterOwner(OwnerDDLParams{ObjectType: "schema", ObjectName: "sales", Owner: "Admin", IncludeObjects: true})
+	if err != nil {
+		t.Fatalf("failed to alter owner: %s", err)
+	}
+
+	assert.Equal(t, "admin", owner.Owner)
+}
@@ -0,0 +1,210 @@
+package redshift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrReadOnly is returned for a statement a read only client refuses to run.
+var ErrReadOnly = errors.New("the provider is read only")
+
+// readOnlyQuerier runs reads on tx and refuses everything else before it
+// reaches the cluster, the session's default_transaction_read_only is the
+// cluster's own guard behind it.
+type readOnlyQuerier struct {
+	tx Querier
+}
+
+func (q readOnlyQuerier) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	if err := checkReadOnly(sql); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	return q.tx.Exec(ctx, sql, arguments...)
+}
+
+func (q readOnlyQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if err := checkReadOnly(sql); err != nil {
+		return nil, err
+	}
+
+	return q.tx.Query(ctx, sql, args...)
+}
+
+func (q readOnlyQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if err := checkReadOnly(sql); err != nil {
+		return errRow{err}
+	}
+
+	return q.tx.QueryRow(ctx, sql, args...)
+}
+
+// a row whose Scan fails with err, as pgx reports the errors of QueryRow.
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
+
+// checkReadOnly refuses the statements isRead does not allow.
+func checkReadOnly(sql string) error {
+	if isRead(sql) {
+		return nil
+	}
+
+	words, statements := scanStatements(sql)
+
+	refused := firstKeyword(sql)
+	switch {
+	case statements > 1:
+		refused = "several statements"
+	case slices.Contains(words, "INTO"):
+		refused += " INTO"
+	}
+
+	return fmt.Errorf("%w, refusing to run %s", ErrReadOnly, refused)
+}
+
+// isRead allows the statements which only read: queries which do not store
+// their result INTO a table, SHOW, and SET of session settings other than
+// those which would lift read only. More than one statement is never allowed,
+// what follows the first is not checked.
+func isRead(sql string) bool {
+	words, statements := scanStatements(sql)
+	if statements > 1 {
+		return false
+	}
+
+	switch firstKeyword(sql) {
+	case "SELECT", "WITH":
+		return !slices.Contains(words, "INTO")
+	case "SHOW":
+		return true
+	case "SET":
+		lower := strings.ToLower(sql)
+		return !strings.Contains(lower, "read_only") && !strings.Contains(lower, "session authorization")
+	}
+
+	return false
+}
+
+// scanStatements returns the words of sql in upper case and the number of
+// statements it holds, skipping quoted text and comments. A statement ends at
+// a semicolon, one with nothing but white space and comments after it is not
+// counted.
+func scanStatements(sql string) ([]string, int) {
+	var words []string
+	statements := 0
+	inStatement := false
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+
+		switch {
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				return words, statements
+			}
+			i += end
+			continue
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return words, statements
+			}
+			i += end + 3
+			continue
+		case c == ';':
+			inStatement = false
+			continue
+		case unicode.IsSpace(rune(c)):
+			continue
+		}
+
+		if !inStatement {
+			inStatement = true
+			statements++
+		}
+
+		switch {
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(sql[i+1:], c)
+			if end < 0 {
+				return words, statements
+			}
+			// a doubled quote continues the quoted text
+			i += end + 1
+		case c == '$':
+			// dollar quoted text, $tag$...$tag$, rather than a parameter
+			if end := strings.IndexByte(sql[i+1:], '$'); end >= 0 && isDollarTag(sql[i+1:i+1+end]) {
+				tag := sql[i : i+end+2]
+				closing := strings.Index(sql[i+len(tag):], tag)
+				if closing < 0 {
+					return words, statements
+				}
+				i += 2*len(tag) + closing - 1
+			}
+		case unicode.IsLetter(rune(c)) || c == '_':
+			end := strings.IndexFunc(sql[i:], func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '$'
+			})
+			if end < 0 {
+				end = len(sql) - i
+			}
+			words = append(words, strings.ToUpper(sql[i:i+end]))
+			i += end - 1
+		}
+	}
+
+	return words, statements
+}
+
+// isDollarTag is whether tag may name dollar quoted text, it is empty or a
+// word not starting with a digit.
+func isDollarTag(tag string) bool {
+	for i, r := range tag {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// firstKeyword is the first word of sql in upper case, after any white
+// space, comments and opening parentheses.
+func firstKeyword(sql string) string {
+	for {
+		trimmed := strings.TrimLeftFunc(sql, func(r rune) bool {
+			return unicode.IsSpace(r) || r == '('
+		})
+
+		switch {
+		case strings.HasPrefix(trimmed, "--"):
+			_, trimmed, _ = strings.Cut(trimmed, "\n")
+		case strings.HasPrefix(trimmed, "/*"):
+			_, trimmed, _ = strings.Cut(trimmed, "*/")
+		default:
+			end := strings.IndexFunc(trimmed, func(r rune) bool {
+				return !unicode.IsLetter(r) && r != '_'
+			})
+			if end < 0 {
+				end = len(trimmed)
+			}
+
+			return strings.ToUpper(trimmed[:end])
+		}
+
+		sql = trimmed
+	}
+}
@@ -0,0 +1,54 @@
+package redshift
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkReadOnly(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		sql         string
+		expectedErr string
+	}{
+		"select":                  {sql: "SELECT usename FROM pg_user_info"},
+		"select_lower_case":       {sql: "\n\t\tselect 1"},
+		"with":                    {sql: "WITH groups_no_users AS (SELECT groname FROM pg_group) SELECT * FROM groups_no_users"},
+		"parenthesised":           {sql: "(SELECT 1) UNION (SELECT 2)"},
+		"comment":                 {sql: "-- find the user\n/* by name */ SELECT 1"},
+		"show":                    {sql: "SHOW enable_case_sensitive_identifier"},
+		"set_session":             {sql: "SET enable_case_sensitive_identifier TO true"},
+		"create":                  {sql: `CREATE USER "bob" PASSWORD DISABLE`, expectedErr: "refusing to run CREATE"},
+		"comment_before_drop":     {sql: "/* SELECT */ DROP GROUP \"devs\"", expectedErr: "refusing to run DROP"},
+		"grant":                   {sql: "grant select on t to bob", expectedErr: "refusing to run GRANT"},
+		"lift_read_only":          {sql: "SET default_transaction_read_only TO off", expectedErr: "refusing to run SET"},
+		"session_authorization":   {sql: "SET SESSION AUTHORIZATION admin", expectedErr: "refusing to run SET"},
+		"trailing_semicolon":      {sql: "SELECT 1;\n-- done\n"},
+		"quoted_semicolon":        {sql: "SELECT ';DROP USER bob' AS note, \"a;b\" FROM t"},
+		"quoted_into":             {sql: "SELECT 'INTO', \"into\" FROM t"},
+		"dollar_quoted":           {sql: "SELECT $$; DROP USER bob$$, $1"},
+		"select_into":             {sql: "SELECT * INTO backup FROM pg_user_info", expectedErr: "refusing to run SELECT INTO"},
+		"with_into":               {sql: "WITH u AS (SELECT 1) select * into temp t FROM u", expectedErr: "refusing to run WITH INTO"},
+		"several_statements":      {sql: "SELECT 1; DROP USER bob", expectedErr: "refusing to run several statements"},
+		"statement_after_comment": {sql: "SHOW search_path; /* then */ GRANT ALL ON t TO bob", expectedErr: "refusing to run several statements"},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkReadOnly(test.sql)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, test.expectedErr)
+			assert.True(t, errors.Is(err, ErrReadOnly))
+		})
+	}
+}
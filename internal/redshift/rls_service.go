@@ -0,0 +1,537 @@
+package redshift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"terraform-provider-redshift/internal/helpers"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type svv_rls_policy struct {
+	PolicyName string  `db:"polname"`
+	Alias      *string `db:"polalias"`
+	Attributes *string `db:"polatts"`
+	Predicate  string  `db:"polqual"`
+}
+
+type RlsPolicy struct {
+	svv_rls_policy
+	Columns map[string]string
+}
+
+type svv_rls_attached_policy struct {
+	SchemaName  string `db:"relschema"`
+	TableName   string `db:"relname"`
+	PolicyName  string `db:"polname"`
+	Grantee     string `db:"grantee"`
+	GranteeKind string `db:"granteekind"`
+}
+
+type RlsAttachment struct {
+	svv_rls_attached_policy
+}
+
+type svv_rls_relation struct {
+	SchemaName      string  `db:"relschema"`
+	TableName       string  `db:"relname"`
+	Enabled         bool    `db:"is_rls_on"`
+	ConjunctionType *string `db:"rls_conjunction_type"`
+}
+
+type TableRls struct {
+	svv_rls_relation
+}
+
+// polatts is a json array describing the columns of the WITH clause.
+type rlsPolicyAttribute struct {
+	Name string `json:"colname"`
+	Type string `json:"type"`
+}
+
+type RlsService struct {
+	exec    Executor
+	ctx     context.Context
+	timeout time.Duration
+}
+
+func NewRlsService(ctx context.Context, exec Executor) (*RlsService, error) {
+	timeout := operationTimeout(ctx)
+
+	return &RlsService{
+		exec:    exec,
+		ctx:     ctx,
+		timeout: timeout,
+	}, nil
+}
+
+func (s *RlsService) FindRlsPolicy(name string) (*RlsPolicy, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	var policy *RlsPolicy
+	err := s.exec.InTx(ctx, "FindRlsPolicy", func(tx Querier) error {
+		var err error
+		policy, err = getRlsPolicyByName(name, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("FindRlsPolicy: Failed to build policy: %w", err)
+		}
+
+		if policy == nil {
+			return fmt.Errorf("FindRlsPolicy: Could not find rls policy with name '%s'", name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (s *RlsService) DropRlsPolicy(name string) error {
+	sql := fmt.Sprintf("DROP RLS POLICY %s", pgx.Identifier{name}.Sanitize())
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, name)
+
+	return s.exec.InTx(ctx, "DropRlsPolicy", func(tx Querier) error {
+		_, err := tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("DropRlsPolicy: Failed to execute: %w", err)
+		}
+
+		return nil
+	})
+}
+
+type CreateRlsPolicyDDLParams struct {
+	Name    string
+	Columns map[string]string
+	Alias   *string
+	Using   string
+}
+
+func (s *RlsService) CreateRlsPolicy(args CreateRlsPolicyDDLParams) (*RlsPolicy, error) {
+	t := `
+		CREATE RLS POLICY {{.Name}}
+			{{if .Columns}}WITH ({{(StringsJoin .Columns ", ")}}){{if .Alias}} AS {{.Alias}}{{end}}{{end}}
+			USING ({{.Using}})
+	`
+	name := args.Name // save this unsanitized for lookup later
+
+	params := struct {
+		Name    string
+		Columns []string
+		Alias   string
+		Using   string
+	}{
+		Name:  pgx.Identifier{args.Name}.Sanitize(),
+		Using: args.Using,
+	}
+
+	if args.Alias != nil {
+		params.Alias = pgx.Identifier{*args.Alias}.Sanitize()
+	}
+
+	// sort the columns so the generated statement is stable
+	columns := make([]string, 0, len(args.Columns))
+	for column := range args.Columns {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	for _, column := range columns {
+		params.Columns = append(params.Columns, fmt.Sprintf("%s %s", pgx.Identifier{column}.Sanitize(), args.Columns[column]))
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, name)
+
+	var policy *RlsPolicy
+	err := s.exec.InTx(ctx, "CreateRlsPolicy", func(tx Querier) error {
+		sql, err := helpers.Merge(t, params)
+		if err != nil {
+			return fmt.Errorf("CreateRlsPolicy: Failed to merge template: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("CreateRlsPolicy: Failed to execute: %w", err)
+		}
+
+		policy, err = getRlsPolicyByName(name, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("CreateRlsPolicy: Failed to getRlsPolicyByName: %w", err)
+		}
+		if policy == nil {
+			return fmt.Errorf("CreateRlsPolicy: Could not find rls policy with name '%s'", name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+type AlterRlsPolicyDDLParams struct {
+	Name  string
+	Using string
+}
+
+func (s *RlsService) AlterRlsPolicy(args AlterRlsPolicyDDLParams) error {
+	name := args.Name // save this unsanitized for the audit
+	args.Name = pgx.Identifier{args.Name}.Sanitize()
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, name)
+
+	return s.exec.InTx(ctx, "AlterRlsPolicy", func(tx Querier) error {
+		// only the predicate of a policy can be altered, everything else requires replacement
+		t := `
+			ALTER RLS POLICY {{.Name}}
+				USING ({{.Using}})
+			`
+
+		sql, err := helpers.Merge(t, args)
+		if err != nil {
+			return fmt.Errorf("AlterRlsPolicy: failed to merge template: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("AlterRlsPolicy: failed to execute: %w", err)
+		}
+
+		return nil
+	})
+}
+
+type RlsAttachmentDDLParams struct {
+	PolicyName  string
+	SchemaName  string
+	TableName   string
+	GranteeType string
+	Grantee     string
+}
+
+func (s *RlsService) FindRlsAttachment(args RlsAttachmentDDLParams) (*RlsAttachment, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	var attachment *RlsAttachment
+	err := s.exec.InTx(ctx, "FindRlsAttachment", func(tx Querier) error {
+		var err error
+		attachment, err = getRlsAttachment(args, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("FindRlsAttachment: Failed to build attachment: %w", err)
+		}
+
+		if attachment == nil {
+			return fmt.Errorf("FindRlsAttachment: Could not find rls policy '%s' attached to '%s.%s'", args.PolicyName, args.SchemaName, args.TableName)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (s *RlsService) AttachRlsPolicy(args RlsAttachmentDDLParams) (*RlsAttachment, error) {
+	t := `
+		ATTACH RLS POLICY {{.PolicyName}}
+			ON {{.Table}}
+			TO {{if eq .GranteeType "public"}}PUBLIC{{else if eq .GranteeType "role"}}ROLE {{.Grantee}}{{else}}{{.Grantee}}{{end}}
+	`
+
+	sql, err := helpers.Merge(t, rlsAttachmentTemplateParams(args))
+	if err != nil {
+		return nil, fmt.Errorf("AttachRlsPolicy: Failed to merge template: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, auditName(args.SchemaName, args.TableName))
+
+	var attachment *RlsAttachment
+	err = s.exec.InTx(ctx, "AttachRlsPolicy", func(tx Querier) error {
+		_, err := tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("AttachRlsPolicy: Failed to execute: %w", err)
+		}
+
+		attachment, err = getRlsAttachment(args, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("AttachRlsPolicy: Failed to getRlsAttachment: %w", err)
+		}
+		if attachment == nil {
+			return fmt.Errorf("AttachRlsPolicy: Could not find rls policy '%s' attached to '%s.%s'", args.PolicyName, args.SchemaName, args.TableName)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (s *RlsService) DetachRlsPolicy(args RlsAttachmentDDLParams) error {
+	t := `
+		DETACH RLS POLICY {{.PolicyName}}
+			ON {{.Table}}
+			FROM {{if eq .GranteeType "public"}}PUBLIC{{else if eq .GranteeType "role"}}ROLE {{.Grantee}}{{else}}{{.Grantee}}{{end}}
+	`
+
+	sql, err := helpers.Merge(t, rlsAttachmentTemplateParams(args))
+	if err != nil {
+		return fmt.Errorf("DetachRlsPolicy: Failed to merge template: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, auditName(args.SchemaName, args.TableName))
+
+	return s.exec.InTx(ctx, "DetachRlsPolicy", func(tx Querier) error {
+		_, err := tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("DetachRlsPolicy: Failed to execute: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (s *RlsService) FindTableRls(schemaName string, tableName string) (*TableRls, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	var table *TableRls
+	err := s.exec.InTx(ctx, "FindTableRls", func(tx Querier) error {
+		var err error
+		table, err = getTableRls(schemaName, tableName, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("FindTableRls: Failed to build table rls: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+type AlterTableRlsDDLParams struct {
+	SchemaName      string
+	TableName       string
+	Enabled         bool
+	ConjunctionType *string
+}
+
+func (s *RlsService) AlterTableRls(args AlterTableRlsDDLParams) (*TableRls, error) {
+	t := `
+		ALTER TABLE {{.Table}}
+			ROW LEVEL SECURITY {{if .Enabled}}ON{{if .ConjunctionType}} CONJUNCTION TYPE {{.ConjunctionType}}{{end}}{{else}}OFF{{end}}
+	`
+
+	params := struct {
+		Table           string
+		Enabled         bool
+		ConjunctionType *string
+	}{
+		Table:           pgx.Identifier{args.SchemaName, args.TableName}.Sanitize(),
+		Enabled:         args.Enabled,
+		ConjunctionType: args.ConjunctionType,
+	}
+
+	sql, err := helpers.Merge(t, params)
+	if err != nil {
+		return nil, fmt.Errorf("AlterTableRls: Failed to merge template: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, auditName(args.SchemaName, args.TableName))
+
+	var table *TableRls
+	err = s.exec.InTx(ctx, "AlterTableRls", func(tx Querier) error {
+		_, err := tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("AlterTableRls: Failed to execute: %w", err)
+		}
+
+		table, err = getTableRls(args.SchemaName, args.TableName, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("AlterTableRls: Failed to getTableRls: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+type rlsAttachmentParams struct {
+	PolicyName  string
+	Table       string
+	GranteeType string
+	Grantee     string
+}
+
+func rlsAttachmentTemplateParams(args RlsAttachmentDDLParams) rlsAttachmentParams {
+	return rlsAttachmentParams{
+		PolicyName:  pgx.Identifier{args.PolicyName}.Sanitize(),
+		Table:       pgx.Identifier{args.SchemaName, args.TableName}.Sanitize(),
+		GranteeType: args.GranteeType,
+		Grantee:     pgx.Identifier{args.Grantee}.Sanitize(),
+	}
+}
+
+func getRlsPolicyByName(name string, ctx context.Context, tx Querier) (*RlsPolicy, error) {
+	sql := `
+	SELECT svv.polname,
+		   svv.polalias,
+		   svv.polatts,
+		   svv.polqual
+	  FROM svv_rls_policy svv
+	 WHERE svv.poldb = current_database()
+	   AND svv.polname = @PolicyName
+	`
+	args := pgx.NamedArgs{"PolicyName": name}
+
+	rows, err := tx.Query(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("getRlsPolicyByName: Failed query execute: %w", err)
+	}
+
+	svv_rls_policy, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[svv_rls_policy])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("getRlsPolicyByName: Failed to collect row: %w", err)
+	}
+
+	columns := map[string]string{}
+	if svv_rls_policy.Attributes != nil && *svv_rls_policy.Attributes != "" {
+		var attributes []rlsPolicyAttribute
+
+		err = json.Unmarshal([]byte(*svv_rls_policy.Attributes), &attributes)
+		if err != nil {
+			return nil, fmt.Errorf("getRlsPolicyByName: Failed to parse policy attributes: %w", err)
+		}
+
+		for _, attribute := range attributes {
+			columns[attribute.Name] = attribute.Type
+		}
+	}
+
+	policy := RlsPolicy{
+		svv_rls_policy: svv_rls_policy,
+		Columns:        columns,
+	}
+
+	return &policy, nil
+}
+
+func getRlsAttachment(args RlsAttachmentDDLParams, ctx context.Context, tx Querier) (*RlsAttachment, error) {
+	sql := `
+	SELECT svv.relschema,
+		   svv.relname,
+		   svv.polname,
+		   svv.grantee,
+		   svv.granteekind
+	  FROM svv_rls_attached_policy svv
+	 WHERE svv.polname = @PolicyName
+	   AND svv.relschema = @SchemaName
+	   AND svv.relname = @TableName
+	   AND svv.grantee = @Grantee
+	`
+	grantee := args.Grantee
+	if args.GranteeType == "public" {
+		grantee = "public"
+	}
+
+	namedArgs := pgx.NamedArgs{
+		"PolicyName": args.PolicyName,
+		"SchemaName": args.SchemaName,
+		"TableName":  args.TableName,
+		"Grantee":    grantee,
+	}
+
+	rows, err := tx.Query(ctx, sql, namedArgs)
+	if err != nil {
+		return nil, fmt.Errorf("getRlsAttachment: Failed query execute: %w", err)
+	}
+
+	svv_rls_attached_policy, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[svv_rls_attached_policy])
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("getRlsAttachment: Failed to collect row: %w", err)
+	}
+
+	attachment := RlsAttachment{
+		svv_rls_attached_policy: svv_rls_attached_policy,
+	}
+
+	return &attachment, nil
+}
+
+// returns a table with row level security off when the table is not rls protected.
+func getTableRls(schemaName string, tableName string, ctx context.Context, tx Querier) (*TableRls, error) {
+	sql := `
+	SELECT svv.relschema,
+		   svv.relname,
+		   svv.is_rls_on,
+		   svv.rls_conjunction_type
+	  FROM svv_rls_relation svv
+	 WHERE svv.datname = current_database()
+	   AND svv.relschema = @SchemaName
+	   AND svv.relname = @TableName
+	`
+	args := pgx.NamedArgs{"SchemaName": schemaName, "TableName": tableName}
+
+	rows, err := tx.Query(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("getTableRls: Failed query execute: %w", err)
+	}
+
+	svv_rls_relation, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[svv_rls_relation])
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			return nil, fmt.Errorf("getTableRls: Failed to collect row: %w", err)
+		}
+
+		svv_rls_relation.SchemaName = schemaName
+		svv_rls_relation.TableName = tableName
+	}
+
+	table := TableRls{
+		svv_rls_relation: svv_rls_relation,
+	}
+
+	return &table, nil
+}
@@ -3,10 +3,8 @@ package redshift
 import (
 	"context"
 	"fmt"
-	"terraform-provider-redshift/internal/helpers"
 	"time"
 
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -22,20 +20,16 @@ type Role struct {
 }
 
 type RoleService struct {
-	cfg     *pgx.ConnConfig
+	exec    Executor
 	ctx     context.Context
 	timeout time.Duration
 }
 
-func NewRoleService(ctx context.Context, cfg *pgx.ConnConfig) (*RoleService, error) {
-	timeout := cfg.ConnectTimeout
-	if cfg.ConnectTimeout <= 0 {
-		tflog.Info(ctx, "No timeout provided, using 5 minutes")
-		timeout = time.Minute * 5
-	}
+func NewRoleService(ctx context.Context, exec Executor) (*RoleService, error) {
+	timeout := operationTimeout(ctx)
 
 	return &RoleService{
-		cfg:     cfg,
+		exec:    exec,
 		ctx:     ctx,
 		timeout: timeout,
 	}, nil
@@ -55,28 +49,22 @@ func (s *RoleService) FindRole(id string) (*Role, error) {
 	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
 	defer cancel()
 
-	conn, err := pgx.ConnectConfig(ctx, s.cfg)
-	if err != nil {
-		return nil, fmt.Errorf("FindRole: Unable to connect %w", err)
-	}
-
-	tx, err := conn.Begin(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("FindRole: Failed to begin transaction: %w", err)
-	}
-
-	role, err := buildRole(sql, args, ctx, tx)
-	if err != nil {
-		return nil, fmt.Errorf("FindRole: Failed to build role: %w", err)
-	}
+	var role *Role
+	err := s.exec.InTx(ctx, "FindRole", func(tx Querier) error {
+		var err error
+		role, err = buildRole(sql, args, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("FindRole: Failed to build role: %w", err)
+		}
 
-	if role == nil {
-		return nil, fmt.Errorf("FindRole: Could not find role with id '%s'", id)
-	}
+		if role == nil {
+			return fmt.Errorf("FindRole: Could not find role with id '%s'", id)
+		}
 
-	err = tx.Commit(ctx)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("FindRole: Failed to commit: %w", err)
+		return nil, err
 	}
 
 	return role, nil
@@ -87,28 +75,16 @@ func (s *RoleService) DropRole(name string) error {
 
 	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
 	defer cancel()
+	ctx = withAuditObject(ctx, name)
 
-	conn, err := pgx.ConnectConfig(ctx, s.cfg)
-	if err != nil {
-		return fmt.Errorf("DropRole: Unable to connect %w", err)
-	}
-
-	tx, err := conn.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("DropRole: Failed to begin transaction: %w", err)
-	}
-
-	_, err = tx.Exec(ctx, sql)
-	if err != nil {
-		return fmt.Errorf("DropRole: Failed to execute: %w", err)
-	}
-
-	err = tx.Commit(ctx)
-	if err != nil {
-		return fmt.Errorf("DropRole: Failed to commit: %w", err)
-	}
+	return s.exec.InTx(ctx, "DropRole", func(tx Querier) error {
+		_, err := tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("DropRole: Failed to execute: %w", err)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 type CreateRoleDDLParams struct {
@@ -117,47 +93,34 @@ type CreateRoleDDLParams struct {
 }
 
 func (s *RoleService) CreateRole(args CreateRoleDDLParams) (*Role, error) {
-	t := `
-		CREATE ROLE {{.Name}}
-			{{if .ExternalId}}EXTERNALID '{{.ExternalId}}' {{end}}
-	`
-	name := args.Name // save this unsanitized for lookup later
-	args.Name = pgx.Identifier{args.Name}.Sanitize()
-
-	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
-	defer cancel()
-
-	conn, err := pgx.ConnectConfig(ctx, s.cfg)
-	if err != nil {
-		return nil, fmt.Errorf("CreateRole: Unable to connect %w", err)
-	}
-
-	tx, err := conn.Begin(ctx)
+	sql, err := RenderCreateRole(args)
 	if err != nil {
-		return nil, fmt.Errorf("CreateRole: Failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("CreateRole: %w", err)
 	}
 
-	sql, err := helpers.Merge(t, args)
-	if err != nil {
-		return nil, fmt.Errorf("CreateRole: Failed to merge template: %w", err)
-	}
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, args.Name)
 
-	_, err = tx.Exec(ctx, sql)
-	if err != nil {
-		return nil, fmt.Errorf("CreateRole: Failed to execute: %w", err)
-	}
+	var role *Role
+	err = s.exec.InTx(ctx, "CreateRole", func(tx Querier) error {
+		_, err := tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("CreateRole: Failed to execute: %w", err)
+		}
 
-	role, err := getRoleByName(name, ctx, tx)
-	if err != nil {
-		return nil, fmt.Errorf("CreateRole: Failed to getRoleByName: %w", err)
-	}
-	if role == nil {
-		return nil, fmt.Errorf("CreateRole: Could not find role with name '%s'", name)
-	}
+		role, err = getRoleByName(args.Name, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("CreateRole: Failed to getRoleByName: %w", err)
+		}
+		if role == nil {
+			return fmt.Errorf("CreateRole: Could not find role with name '%s'", args.Name)
+		}
 
-	err = tx.Commit(ctx)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("CreateRole: Failed to commit: %w", err)
+		return nil, err
 	}
 
 	return role, nil
@@ -170,52 +133,27 @@ type AlterRoleDDLParams struct {
 }
 
 func (s *RoleService) AlterRole(args AlterRoleDDLParams) error {
-	args.Name = pgx.Identifier{args.Name}.Sanitize()
-
-	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
-	defer cancel()
-
-	conn, err := pgx.ConnectConfig(ctx, s.cfg)
+	sql, err := RenderAlterRole(args)
 	if err != nil {
-		return fmt.Errorf("AlterRole: Unable to connect %w", err)
+		return fmt.Errorf("AlterRole: %w", err)
 	}
 
-	tx, err := conn.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("AlterRole: Failed to begin transaction: %w", err)
-	}
-
-	if args.RenameTo != nil {
-		rn := pgx.Identifier{*args.RenameTo}.Sanitize()
-		args.RenameTo = &rn
-	}
-
-	t := `
-		ALTER ROLE {{.Name}}
-			{{if .RenameTo}}RENAME TO {{.RenameTo}}{{end}}
-			{{if .ExternalId}}EXTERNALID '{{.ExternalId}}' {{end}}
-		`
-
-	sql, err := helpers.Merge(t, args)
-	if err != nil {
-		return fmt.Errorf("AlterRole: failed to merge template: %w", err)
-	}
-
-	_, err = tx.Exec(ctx, sql)
-	if err != nil {
-		return fmt.Errorf("AlterRole: failed to execute: %w", err)
-	}
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, args.Name)
 
-	err = tx.Commit(ctx)
-	if err != nil {
-		return fmt.Errorf("AlterRole: Failed to commit: %w", err)
-	}
+	return s.exec.InTx(ctx, "AlterRole", func(tx Querier) error {
+		_, err := tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("AlterRole: failed to execute: %w", err)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // hidden from outside the package, expect that callers use the ById variant.
-func getRoleByName(name string, ctx context.Context, tx pgx.Tx) (*Role, error) {
+func getRoleByName(name string, ctx context.Context, tx Querier) (*Role, error) {
 	sql := `
 	SELECT svv.external_id,
 		   svv.role_id::varchar,
@@ -229,7 +167,7 @@ func getRoleByName(name string, ctx context.Context, tx pgx.Tx) (*Role, error) {
 	return buildRole(sql, args, ctx, tx)
 }
 
-func buildRole(sql string, args pgx.NamedArgs, ctx context.Context, tx pgx.Tx) (*Role, error) {
+func buildRole(sql string, args pgx.NamedArgs, ctx context.Context, tx Querier) (*Role, error) {
 	rows, err := tx.Query(ctx, sql, args)
 	if err != nil {
 		return nil, fmt.Errorf("buildRole: Failed query execute: %w", err)
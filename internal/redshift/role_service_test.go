@@ -0,0 +1,103 @@
+package redshift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+const roleByNameSQL = `
+	SELECT svv.external_id,
+		   svv.role_id::varchar,
+		   svv.role_owner,
+		   svv.role_name
+	  FROM svv_roles svv
+	 WHERE svv.role_name = @RoleName
+	`
+
+func Test_RoleService(t *testing.T) {
+	t.Parallel()
+
+	externalId := "ext-1"
+	analysts := "analysts"
+
+	type testCase struct {
+		run    func(svc *RoleService) error
+		expect func(mock pgxmock.PgxConnIface)
+	}
+	tests := map[string]testCase{
+		"create": {
+			run: func(svc *RoleService) error {
+				role, err := svc.CreateRole(CreateRoleDDLParams{Name: "readers", ExternalId: &externalId})
+				if err == nil {
+					assert.Equal(t, "300", role.Id)
+					assert.Equal(t, &externalId, role.ExternalId)
+				}
+				return err
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`CREATE ROLE "readers" EXTERNALID 'ext-1'`).
+					WillReturnResult(pgxmock.NewResult("CREATE ROLE", 0))
+				mock.ExpectQuery(roleByNameSQL).
+					WithArgs(pgx.NamedArgs{"RoleName": "readers"}).
+					WillReturnRows(pgxmock.NewRows([]string{"external_id", "role_id", "role_owner", "role_name"}).
+						AddRow(&externalId, "300", "admin", "readers"))
+				mock.ExpectCommit()
+			},
+		},
+		"alter_rename": {
+			run: func(svc *RoleService) error {
+				return svc.AlterRole(AlterRoleDDLParams{Name: "readers", RenameTo: &analysts})
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`ALTER ROLE "readers" RENAME TO "analysts"`).
+					WillReturnResult(pgxmock.NewResult("ALTER ROLE", 0))
+				mock.ExpectCommit()
+			},
+		},
+		"alter_external_id": {
+			run: func(svc *RoleService) error {
+				return svc.AlterRole(AlterRoleDDLParams{Name: "readers", ExternalId: &externalId})
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`ALTER ROLE "readers" EXTERNALID 'ext-1'`).
+					WillReturnResult(pgxmock.NewResult("ALTER ROLE", 0))
+				mock.ExpectCommit()
+			},
+		},
+		"drop": {
+			run: func(svc *RoleService) error {
+				return svc.DropRole("readers")
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`DROP ROLE "readers"`).
+					WillReturnResult(pgxmock.NewResult("DROP ROLE", 0))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			executor, mock := newMockExecutor(t)
+			test.expect(mock)
+
+			svc, err := NewRoleService(context.Background(), executor)
+			if err != nil {
+				t.Fatalf("failed to create service: %s", err)
+			}
+
+			assert.NoError(t, test.run(svc))
+		})
+	}
+}
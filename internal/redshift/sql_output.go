@@ -0,0 +1,108 @@
+package redshift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"terraform-provider-redshift/internal/helpers"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrSQLOutputOnly is returned for a transaction whose statements were written
+// to the SQL output file in place of running them.
+var ErrSQLOutputOnly = errors.New("the statements were written to the SQL output file and not run")
+
+// SQLOutput is a file the statements which change redshift are appended to,
+// with their passwords redacted, for review before or after they run.
+type SQLOutput struct {
+	Path string
+	// writes the statements without running them, the transaction is rolled
+	// back and fails with ErrSQLOutputOnly
+	Only bool
+
+	// resources are applied in parallel, their statements are not interleaved
+	mu sync.Mutex
+}
+
+// write appends the statements of the transaction name ran in database, as a
+// script which can be run as it is.
+func (o *SQLOutput) write(name string, database string, statements []string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- %s %s in %s\n", time.Now().UTC().Format(time.RFC3339), name, database)
+	for _, statement := range statements {
+		b.WriteString(helpers.Redact(compactSQL(statement)))
+		b.WriteString(";\n")
+	}
+	b.WriteString("\n")
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	f, err := os.OpenFile(o.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("write: Failed to open %s: %w", o.Path, err)
+	}
+
+	_, err = f.WriteString(b.String())
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("write: Failed to write %s: %w", o.Path, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write: Failed to close %s: %w", o.Path, err)
+	}
+
+	return nil
+}
+
+// captureQuerier records every statement sent with Exec other than queries,
+// the services send their DDL with it. Session settings are recorded too, the
+// script would behave differently without them, but they run even with only
+// set as the reads after them depend on them.
+type captureQuerier struct {
+	tx         Querier
+	only       bool
+	statements []string
+}
+
+func (q *captureQuerier) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	switch firstKeyword(sql) {
+	case "SELECT", "WITH", "SHOW":
+		return q.tx.Exec(ctx, sql, arguments...)
+	}
+
+	q.statements = append(q.statements, sql)
+	if q.only && !isRead(sql) {
+		return pgconn.CommandTag{}, nil
+	}
+
+	return q.tx.Exec(ctx, sql, arguments...)
+}
+
+func (q *captureQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return q.tx.Query(ctx, sql, args...)
+}
+
+func (q *captureQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return q.tx.QueryRow(ctx, sql, args...)
+}
+
+// changed reports whether a statement other than a session setting was
+// recorded, a transaction which only set and read changed nothing worth
+// writing.
+func (q *captureQuerier) changed() bool {
+	for _, statement := range q.statements {
+		if !isRead(statement) {
+			return true
+		}
+	}
+
+	return false
+}
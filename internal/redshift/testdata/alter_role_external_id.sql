@@ -0,0 +1 @@
+ALTER ROLE "readers" EXTERNALID 'ext-1';
@@ -0,0 +1 @@
+ALTER ROLE "odd ""name"" with spaces" RENAME TO "robert";
@@ -0,0 +1 @@
+ALTER ROLE "readers" RENAME TO "Analysts" EXTERNALID 'ext-1';
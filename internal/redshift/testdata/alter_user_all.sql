@@ -0,0 +1,2 @@
+ALTER USER "bob" RENAME TO "robert";
+ALTER USER "robert" PASSWORD 'Secret123' CREATEDB NOCREATEUSER SYSLOG ACCESS UNRESTRICTED VALID UNTIL '2030-01-01 00:00:00' CONNECTION LIMIT 5 RESET SESSION TIMEOUT EXTERNALID 'ext-1';
@@ -0,0 +1 @@
+ALTER USER "bob" CONNECTION LIMIT 5;
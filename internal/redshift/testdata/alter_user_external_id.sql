@@ -0,0 +1 @@
+ALTER USER "bob" EXTERNALID 'ext-1';
@@ -0,0 +1 @@
+ALTER USER "bob" NOCREATEUSER;
@@ -0,0 +1,2 @@
+ALTER USER "odd ""name"" with spaces" RENAME TO "Analysts";
+ALTER USER "Analysts" PASSWORD 'Secret123';
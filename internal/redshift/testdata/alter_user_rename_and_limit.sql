@@ -0,0 +1,2 @@
+ALTER USER "bob" RENAME TO "robert";
+ALTER USER "robert" CONNECTION LIMIT 5;
@@ -0,0 +1 @@
+ALTER USER "bob" SESSION TIMEOUT 120;
@@ -0,0 +1 @@
+ALTER USER "bob" SYSLOG ACCESS UNRESTRICTED;
@@ -0,0 +1 @@
+ALTER USER "bob" VALID UNTIL '2030-01-01 00:00:00';
@@ -0,0 +1 @@
+CREATE GROUP "admins";
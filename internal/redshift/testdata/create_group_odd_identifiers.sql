@@ -0,0 +1 @@
+CREATE GROUP "odd ""name"" with spaces" WITH USER "o""brien", "with space";
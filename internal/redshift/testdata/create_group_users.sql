@@ -0,0 +1 @@
+CREATE GROUP "Analysts" WITH USER "alice", "bob";
@@ -0,0 +1 @@
+CREATE ROLE "odd ""name"" with spaces";
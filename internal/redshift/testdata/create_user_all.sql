@@ -0,0 +1 @@
+CREATE USER "bob" PASSWORD 'Secret123' CREATEDB CREATEUSER SYSLOG ACCESS UNRESTRICTED VALID UNTIL '2030-01-01 00:00:00' CONNECTION LIMIT 5 SESSION TIMEOUT 120 EXTERNALID 'ext-1';
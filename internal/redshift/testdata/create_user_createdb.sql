@@ -0,0 +1 @@
+CREATE USER "bob" PASSWORD DISABLE CREATEDB NOCREATEUSER SYSLOG ACCESS RESTRICTED VALID UNTIL 'infinity' CONNECTION LIMIT UNLIMITED;
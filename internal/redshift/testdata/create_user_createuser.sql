@@ -0,0 +1 @@
+CREATE USER "bob" PASSWORD DISABLE NOCREATEDB CREATEUSER SYSLOG ACCESS RESTRICTED VALID UNTIL 'infinity' CONNECTION LIMIT UNLIMITED;
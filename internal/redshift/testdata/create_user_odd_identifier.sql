@@ -0,0 +1 @@
+CREATE USER "odd ""name"" with spaces" PASSWORD DISABLE NOCREATEDB NOCREATEUSER SYSLOG ACCESS RESTRICTED VALID UNTIL 'infinity' CONNECTION LIMIT UNLIMITED;
@@ -0,0 +1 @@
+CREATE USER "bob" PASSWORD 'Secret123' NOCREATEDB NOCREATEUSER SYSLOG ACCESS RESTRICTED VALID UNTIL 'infinity' CONNECTION LIMIT UNLIMITED;
@@ -0,0 +1 @@
+CREATE USER "bob" PASSWORD 'two  spaces' NOCREATEDB NOCREATEUSER SYSLOG ACCESS RESTRICTED VALID UNTIL 'infinity' CONNECTION LIMIT UNLIMITED;
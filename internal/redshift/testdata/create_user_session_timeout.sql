@@ -0,0 +1 @@
+CREATE USER "bob" PASSWORD DISABLE NOCREATEDB NOCREATEUSER SYSLOG ACCESS RESTRICTED VALID UNTIL 'infinity' CONNECTION LIMIT UNLIMITED SESSION TIMEOUT 120;
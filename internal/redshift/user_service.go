@@ -3,10 +3,8 @@ package redshift
 import (
 	"context"
 	"fmt"
-	"terraform-provider-redshift/internal/helpers"
 	"time"
 
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -32,20 +30,16 @@ type User struct {
 }
 
 type UserService struct {
-	cfg     *pgx.ConnConfig
+	exec    Executor
 	ctx     context.Context
 	timeout time.Duration
 }
 
-func NewUserService(ctx context.Context, cfg *pgx.ConnConfig) (*UserService, error) {
-	timeout := cfg.ConnectTimeout
-	if cfg.ConnectTimeout <= 0 {
-		tflog.Info(ctx, "No timeout provided, using 5 minutes")
-		timeout = time.Minute * 5
-	}
+func NewUserService(ctx context.Context, exec Executor) (*UserService, error) {
+	timeout := operationTimeout(ctx)
 
 	return &UserService{
-		cfg:     cfg,
+		exec:    exec,
 		ctx:     ctx,
 		timeout: timeout,
 	}, nil
@@ -69,28 +63,22 @@ func (s *UserService) FindUser(id string) (*User, error) {
 	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
 	defer cancel()
 
-	conn, err := pgx.ConnectConfig(ctx, s.cfg)
-	if err != nil {
-		return nil, fmt.Errorf("FindUser: Unable to connect %w", err)
-	}
-
-	tx, err := conn.Begin(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("FindUser: Failed to begin transaction: %w", err)
-	}
-
-	user, err := buildUser(sql, args, ctx, tx)
-	if err != nil {
-		return nil, fmt.Errorf("FindUser: Failed to build user: %w", err)
-	}
+	var user *User
+	err := s.exec.InTx(ctx, "FindUser", func(tx Querier) error {
+		var err error
+		user, err = buildUser(sql, args, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("FindUser: Failed to build user: %w", err)
+		}
 
-	if user == nil {
-		return nil, fmt.Errorf("FindUser: Could not find user with id '%s'", id)
-	}
+		if user == nil {
+			return fmt.Errorf("FindUser: Could not find user with id '%s'", id)
+		}
 
-	err = tx.Commit(ctx)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("FindUser: Failed to commit: %w", err)
+		return nil, err
 	}
 
 	return user, nil
@@ -101,28 +89,16 @@ func (s *UserService) DropUser(name string) error {
 
 	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
 	defer cancel()
+	ctx = withAuditObject(ctx, name)
 
-	conn, err := pgx.ConnectConfig(ctx, s.cfg)
-	if err != nil {
-		return fmt.Errorf("DropUser: Unable to connect %w", err)
-	}
-
-	tx, err := conn.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("DropUser: Failed to begin transaction: %w", err)
-	}
-
-	_, err = tx.Exec(ctx, sql)
-	if err != nil {
-		return fmt.Errorf("DropUser: Failed to execute: %w", err)
-	}
-
-	err = tx.Commit(ctx)
-	if err != nil {
-		return fmt.Errorf("DropUser: Failed to commit: %w", err)
-	}
+	return s.exec.InTx(ctx, "DropUser", func(tx Querier) error {
+		_, err := tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("DropUser: Failed to execute: %w", err)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 type CreateUserDDLParams struct {
@@ -138,54 +114,34 @@ type CreateUserDDLParams struct {
 }
 
 func (s *UserService) CreateUser(args CreateUserDDLParams) (*User, error) {
-	t := `
-		CREATE USER {{.Name}}
-		    PASSWORD {{if .Password}}'{{.Password}}'{{else}}DISABLE{{end}}
-			{{if .CreateDb}}CREATEDB{{else}}NOCREATEDB{{end}}
-			{{if .CreateUser}}CREATEUSER{{else}}NOCREATEUSER{{end}}
-			SYSLOG ACCESS {{.SyslogAccess}}
-			VALID UNTIL '{{.ValidUntil}}'
-			CONNECTION LIMIT {{.ConnectionLimit}}
-			{{if gt .SessionTimeout 0}}SESSION TIMEOUT {{.SessionTimeout}}{{end}}
-			{{if .ExternalId}}EXTERNALID '{{.ExternalId}}' {{end}}
-	`
-	name := args.Name // save this unsanitized for lookup later
-	args.Name = pgx.Identifier{args.Name}.Sanitize()
-
-	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
-	defer cancel()
-
-	conn, err := pgx.ConnectConfig(ctx, s.cfg)
+	sql, err := RenderCreateUser(args)
 	if err != nil {
-		return nil, fmt.Errorf("CreateUser: Unable to connect %w", err)
+		return nil, fmt.Errorf("CreateUser: %w", err)
 	}
 
-	tx, err := conn.Begin(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("CreateUser: Failed to begin transaction: %w", err)
-	}
-
-	sql, err := helpers.Merge(t, args)
-	if err != nil {
-		return nil, fmt.Errorf("CreateUser: Failed to merge template: %w", err)
-	}
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, args.Name)
 
-	_, err = tx.Exec(ctx, sql)
-	if err != nil {
-		return nil, fmt.Errorf("CreateUser: Failed to execute: %w", err)
-	}
+	var user *User
+	err = s.exec.InTx(ctx, "CreateUser", func(tx Querier) error {
+		_, err := tx.Exec(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("CreateUser: Failed to execute: %w", err)
+		}
 
-	user, err := getUserByName(name, ctx, tx)
-	if err != nil {
-		return nil, fmt.Errorf("CreateUser: Failed to getUserByName: %w", err)
-	}
-	if user == nil {
-		return nil, fmt.Errorf("CreateUser: Could not find user with name '%s'", name)
-	}
+		user, err = getUserByName(args.Name, ctx, tx)
+		if err != nil {
+			return fmt.Errorf("CreateUser: Failed to getUserByName: %w", err)
+		}
+		if user == nil {
+			return fmt.Errorf("CreateUser: Could not find user with name '%s'", args.Name)
+		}
 
-	err = tx.Commit(ctx)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("CreateUser: Failed to commit: %w", err)
+		return nil, err
 	}
 
 	return user, nil
@@ -205,71 +161,28 @@ type AlterUserDDLParams struct {
 }
 
 func (s *UserService) AlterUser(args AlterUserDDLParams) error {
-	args.Name = pgx.Identifier{args.Name}.Sanitize()
-
-	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
-	defer cancel()
-
-	conn, err := pgx.ConnectConfig(ctx, s.cfg)
-	if err != nil {
-		return fmt.Errorf("AlterUser: Unable to connect %w", err)
-	}
-
-	tx, err := conn.Begin(ctx)
+	statements, err := RenderAlterUser(args)
 	if err != nil {
-		return fmt.Errorf("AlterUser: Failed to begin transaction: %w", err)
+		return fmt.Errorf("AlterUser: %w", err)
 	}
 
-	// Undocumented, redshift must perform a rename without other options; it is a syntax error otherwise
-	if args.RenameTo != nil {
-		rn := pgx.Identifier{*args.RenameTo}.Sanitize()
-		args.RenameTo = &rn
-
-		rename := `
-			ALTER USER {{.Name}} RENAME TO {{.RenameTo}}
-		`
-		sql, err := helpers.Merge(rename, args)
-		if err != nil {
-			return fmt.Errorf("AlterUser: failed to merge rename template: %w", err)
-		}
-
-		_, err = tx.Exec(ctx, sql)
-		if err != nil {
-			return fmt.Errorf("AlterUser: failed to execute rename: %w", err)
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+	ctx = withAuditObject(ctx, args.Name)
+
+	return s.exec.InTx(ctx, "AlterUser", func(tx Querier) error {
+		for _, sql := range statements {
+			_, err := tx.Exec(ctx, sql)
+			if err != nil {
+				return fmt.Errorf("AlterUser: failed to execute: %w", err)
+			}
 		}
-	}
-
-	t := `
-		ALTER USER {{if .RenameTo}}{{.RenameTo}}{{else}}{{.Name}}{{end}}
-		    {{if .Password}}PASSWORD {{if (DerefString .Password)}}'{{.Password}}'{{else}}DISABLE{{end}}{{end}}
-			{{if .CreateDb}}{{if (DerefBool .CreateDb)}}CREATEDB{{else}}NOCREATEDB{{end}}{{end}}
-			{{if .CreateUser}}{{if (DerefBool .CreateUser)}}CREATEUSER{{else}}NOCREATEUSER{{end}}{{end}}
-			{{if .SyslogAccess}}SYSLOG ACCESS {{.SyslogAccess}}{{end}}
-			{{if .ValidUntil}}VALID UNTIL '{{.ValidUntil}}'{{end}}
-			{{if .ConnectionLimit}}CONNECTION LIMIT {{.ConnectionLimit}}{{end}}
-			{{if .SessionTimeout}}{{if gt (DerefInt64 .SessionTimeout) 0}}SESSION TIMEOUT {{.SessionTimeout}}{{else}}RESET SESSION TIMEOUT{{end}}{{end}}
-			{{if .ExternalId}}EXTERNALID '{{.ExternalId}}' {{end}}
-		`
-
-	sql, err := helpers.Merge(t, args)
-	if err != nil {
-		return fmt.Errorf("AlterUser: failed to merge template: %w", err)
-	}
-
-	_, err = tx.Exec(ctx, sql)
-	if err != nil {
-		return fmt.Errorf("AlterUser: failed to execute: %w", err)
-	}
-
-	err = tx.Commit(ctx)
-	if err != nil {
-		return fmt.Errorf("AlterUser: Failed to commit: %w", err)
-	}
 
-	return nil
+		return nil
+	})
 }
 
-func getUserValidUntil(id string, ctx context.Context, tx pgx.Tx) (*pg_user_info, error) {
+func getUserValidUntil(id string, ctx context.Context, tx Querier) (*pg_user_info, error) {
 	sql := `
 		select coalesce(valuntil::timestamp, 'infinity') as valid_until
 		  from pg_user_info pg
@@ -291,7 +204,7 @@ func getUserValidUntil(id string, ctx context.Context, tx pgx.Tx) (*pg_user_info
 }
 
 // hidden from outside the package, expect that callers use the ById variant.
-func getUserByName(name string, ctx context.Context, tx pgx.Tx) (*User, error) {
+func getUserByName(name string, ctx context.Context, tx Querier) (*User, error) {
 	sql := `
 	SELECT svv.connection_limit,
 		   svv.createdb,
@@ -309,7 +222,7 @@ func getUserByName(name string, ctx context.Context, tx pgx.Tx) (*User, error) {
 	return buildUser(sql, args, ctx, tx)
 }
 
-func buildUser(sql string, args pgx.NamedArgs, ctx context.Context, tx pgx.Tx) (*User, error) {
+func buildUser(sql string, args pgx.NamedArgs, ctx context.Context, tx Querier) (*User, error) {
 	rows, err := tx.Query(ctx, sql, args)
 	if err != nil {
 		return nil, fmt.Errorf("buildUser: Failed query execute: %w", err)
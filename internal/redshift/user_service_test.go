@@ -0,0 +1,165 @@
+package redshift
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	userByNameSQL = `
+	SELECT svv.connection_limit,
+		   svv.createdb,
+		   svv.superuser,
+		   svv.external_user_id,
+		   svv.user_id::varchar,
+		   svv.session_timeout,
+		   svv.syslog_access,
+		   svv.user_name
+	  FROM svv_user_info svv
+	 WHERE svv.user_name = @UserName
+	`
+	userValidUntilSQL = `
+		select coalesce(valuntil::timestamp, 'infinity') as valid_until
+		  from pg_user_info pg
+		 where usesysid = @UserId
+	`
+)
+
+func expectUserLookup(mock pgxmock.PgxConnIface, name string) {
+	mock.ExpectQuery(userByNameSQL).
+		WithArgs(pgx.NamedArgs{"UserName": name}).
+		WillReturnRows(pgxmock.NewRows([]string{"connection_limit", "createdb", "superuser", "external_user_id", "user_id", "session_timeout", "syslog_access", "user_name"}).
+			AddRow("UNLIMITED", false, false, nil, "100", int64(0), "RESTRICTED", name))
+	mock.ExpectQuery(userValidUntilSQL).
+		WithArgs(pgx.NamedArgs{"UserId": "100"}).
+		WillReturnRows(pgxmock.NewRows([]string{"valid_until"}).
+			AddRow(pgtype.Timestamp{InfinityModifier: pgtype.Infinity, Valid: true}))
+}
+
+func Test_UserService(t *testing.T) {
+	t.Parallel()
+
+	password := "Secret123"
+	robert := "robert"
+	limit := "5"
+	timeout := int64(0)
+
+	type testCase struct {
+		run         func(svc *UserService) error
+		expect      func(mock pgxmock.PgxConnIface)
+		expectedErr bool
+	}
+	tests := map[string]testCase{
+		"create": {
+			run: func(svc *UserService) error {
+				user, err := svc.CreateUser(CreateUserDDLParams{
+					Name:            "bob",
+					Password:        &password,
+					CreateDb:        true,
+					SyslogAccess:    "RESTRICTED",
+					ValidUntil:      "infinity",
+					ConnectionLimit: "UNLIMITED",
+				})
+				if err == nil {
+					assert.Equal(t, "100", user.Id)
+					assert.Equal(t, "infinity", user.ValidUntil)
+				}
+				return err
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`CREATE USER "bob" PASSWORD 'Secret123' CREATEDB NOCREATEUSER SYSLOG ACCESS RESTRICTED VALID UNTIL 'infinity' CONNECTION LIMIT UNLIMITED`).
+					WillReturnResult(pgxmock.NewResult("CREATE USER", 0))
+				expectUserLookup(mock, "bob")
+				mock.ExpectCommit()
+			},
+		},
+		"create_fails": {
+			run: func(svc *UserService) error {
+				_, err := svc.CreateUser(CreateUserDDLParams{Name: "bob", SyslogAccess: "RESTRICTED", ValidUntil: "infinity", ConnectionLimit: "UNLIMITED"})
+				return err
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`CREATE USER "bob" PASSWORD DISABLE NOCREATEDB NOCREATEUSER SYSLOG ACCESS RESTRICTED VALID UNTIL 'infinity' CONNECTION LIMIT UNLIMITED`).
+					WillReturnError(errors.New(`user "bob" already exists`))
+				mock.ExpectRollback()
+			},
+			expectedErr: true,
+		},
+		"alter_renames_first": {
+			run: func(svc *UserService) error {
+				return svc.AlterUser(AlterUserDDLParams{Name: "bob", RenameTo: &robert, ConnectionLimit: &limit, SessionTimeout: &timeout})
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`ALTER USER "bob" RENAME TO "robert"`).
+					WillReturnResult(pgxmock.NewResult("ALTER USER", 0))
+				mock.ExpectExec(`ALTER USER "robert" CONNECTION LIMIT 5 RESET SESSION TIMEOUT`).
+					WillReturnResult(pgxmock.NewResult("ALTER USER", 0))
+				mock.ExpectCommit()
+			},
+		},
+		"alter_rename_only": {
+			run: func(svc *UserService) error {
+				return svc.AlterUser(AlterUserDDLParams{Name: "bob", RenameTo: &robert})
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`ALTER USER "bob" RENAME TO "robert"`).
+					WillReturnResult(pgxmock.NewResult("ALTER USER", 0))
+				mock.ExpectCommit()
+			},
+		},
+		"alter_password": {
+			run: func(svc *UserService) error {
+				return svc.AlterUser(AlterUserDDLParams{Name: "bob", Password: &password})
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`ALTER USER "bob" PASSWORD 'Secret123'`).
+					WillReturnResult(pgxmock.NewResult("ALTER USER", 0))
+				mock.ExpectCommit()
+			},
+		},
+		"drop": {
+			run: func(svc *UserService) error {
+				return svc.DropUser("bob")
+			},
+			expect: func(mock pgxmock.PgxConnIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`DROP USER "bob"`).
+					WillReturnResult(pgxmock.NewResult("DROP USER", 0))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			executor, mock := newMockExecutor(t)
+			test.expect(mock)
+
+			svc, err := NewUserService(context.Background(), executor)
+			if err != nil {
+				t.Fatalf("failed to create service: %s", err)
+			}
+
+			err = test.run(svc)
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+// Package serverless looks up the endpoint of a Redshift Serverless workgroup
+// and the temporary IAM credentials for connecting to it.
+package serverless
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftserverless"
+	"github.com/jackc/pgx/v5"
+)
+
+// credentials are fetched again this long before they expire, so that a
+// connection is never attempted with ones about to lapse.
+const refreshMargin = time.Minute
+
+// API is the part of the redshift-serverless client the provider uses, the
+// SDK client in the provider and a stub in tests.
+type API interface {
+	GetWorkgroup(ctx context.Context, params *redshiftserverless.GetWorkgroupInput, optFns ...func(*redshiftserverless.Options)) (*redshiftserverless.GetWorkgroupOutput, error)
+	GetCredentials(ctx context.Context, params *redshiftserverless.GetCredentialsInput, optFns ...func(*redshiftserverless.Options)) (*redshiftserverless.GetCredentialsOutput, error)
+}
+
+var _ API = &redshiftserverless.Client{}
+
+// NewAPI builds a client from the AWS credentials of the environment. An
+// empty endpoint leaves the SDK to resolve it from the region.
+func NewAPI(ctx context.Context, region string, endpoint string) (API, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("NewAPI: Failed to load AWS config: %w", err)
+	}
+
+	return redshiftserverless.NewFromConfig(cfg, func(o *redshiftserverless.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	}), nil
+}
+
+type Endpoint struct {
+	Host string
+	Port int64
+}
+
+// ResolveEndpoint returns the address the workgroup accepts connections on.
+func ResolveEndpoint(ctx context.Context, api API, workgroup string) (*Endpoint, error) {
+	out, err := api.GetWorkgroup(ctx, &redshiftserverless.GetWorkgroupInput{
+		WorkgroupName: aws.String(workgroup),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ResolveEndpoint: Failed to get workgroup '%s': %w", workgroup, err)
+	}
+
+	if out.Workgroup == nil || out.Workgroup.Endpoint == nil || out.Workgroup.Endpoint.Address == nil || out.Workgroup.Endpoint.Port == nil {
+		return nil, fmt.Errorf("ResolveEndpoint: Workgroup '%s' has no endpoint yet", workgroup)
+	}
+
+	return &Endpoint{
+		Host: *out.Workgroup.Endpoint.Address,
+		Port: int64(*out.Workgroup.Endpoint.Port),
+	}, nil
+}
+
+// EndpointSource looks up the endpoint of a workgroup when it is first
+// needed and keeps it, the address does not change while the provider runs.
+// It is safe for concurrent use, resources connect in parallel.
+type EndpointSource struct {
+	api       API
+	workgroup string
+
+	mu      sync.Mutex
+	current *Endpoint
+}
+
+func NewEndpointSource(api API, workgroup string) *EndpointSource {
+	return &EndpointSource{
+		api:       api,
+		workgroup: workgroup,
+	}
+}
+
+// Get returns the endpoint, looking it up when it is not known yet. A failed
+// lookup is tried again on the next call, the workgroup may still be creating.
+func (s *EndpointSource) Get(ctx context.Context) (*Endpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current != nil {
+		return s.current, nil
+	}
+
+	endpoint, err := ResolveEndpoint(ctx, s.api, s.workgroup)
+	if err != nil {
+		return nil, err
+	}
+
+	s.current = endpoint
+
+	return endpoint, nil
+}
+
+// BeforeConnect fills in the host and port a connection config leaves empty
+// with the endpoint, in its fallbacks and as the server name of its TLS
+// configs too, see redshift.Client.
+func (s *EndpointSource) BeforeConnect(ctx context.Context, cfg *pgx.ConnConfig) error {
+	if cfg.Host != "" && cfg.Port != 0 {
+		return nil
+	}
+
+	endpoint, err := s.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	host, port := cfg.Host, cfg.Port
+	if host == "" {
+		host = endpoint.Host
+	}
+	if port == 0 {
+		port = uint16(endpoint.Port)
+	}
+
+	tlsConfigs := []*tls.Config{cfg.TLSConfig}
+	cfg.Host, cfg.Port = host, port
+	for _, fallback := range cfg.Fallbacks {
+		fallback.Host, fallback.Port = host, port
+		tlsConfigs = append(tlsConfigs, fallback.TLSConfig)
+	}
+
+	// as pgx derives it from the host, unless ssl_server_name set one
+	for _, tlsConfig := range tlsConfigs {
+		if tlsConfig != nil && tlsConfig.ServerName == "" && net.ParseIP(host) == nil {
+			tlsConfig.ServerName = host
+		}
+	}
+
+	return nil
+}
+
+type Credentials struct {
+	Username   string
+	Password   string
+	Expiration time.Time
+}
+
+// CredentialSource hands out temporary credentials for a workgroup and
+// fetches new ones when they are about to expire. It is safe for concurrent
+// use, resources connect in parallel.
+type CredentialSource struct {
+	api       API
+	workgroup string
+	dbname    string
+
+	mu      sync.Mutex
+	current *Credentials
+	now     func() time.Time
+}
+
+func NewCredentialSource(api API, workgroup string, dbname string) *CredentialSource {
+	return &CredentialSource{
+		api:       api,
+		workgroup: workgroup,
+		dbname:    dbname,
+		now:       time.Now,
+	}
+}
+
+// Get returns the current credentials, fetching them when there are none or
+// they expire within the refresh margin.
+func (s *CredentialSource) Get(ctx context.Context) (*Credentials, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current != nil && s.now().Add(refreshMargin).Before(s.current.Expiration) {
+		return s.current, nil
+	}
+
+	out, err := s.api.GetCredentials(ctx, &redshiftserverless.GetCredentialsInput{
+		WorkgroupName: aws.String(s.workgroup),
+		DbName:        aws.String(s.dbname),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CredentialSource: Failed to get credentials for workgroup '%s': %w", s.workgroup, err)
+	}
+
+	if out.DbUser == nil || out.DbPassword == nil {
+		return nil, fmt.Errorf("CredentialSource: Workgroup '%s' returned no credentials", s.workgroup)
+	}
+
+	creds := &Credentials{
+		Username: *out.DbUser,
+		Password: *out.DbPassword,
+	}
+	if out.Expiration != nil {
+		creds.Expiration = *out.Expiration
+	}
+
+	s.current = creds
+
+	return creds, nil
+}
+
+// BeforeConnect puts the current credentials into a connection config, see
+// redshift.Client.
+func (s *CredentialSource) BeforeConnect(ctx context.Context, cfg *pgx.ConnConfig) error {
+	creds, err := s.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg.User = creds.Username
+	cfg.Password = creds.Password
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package serverless
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftserverless"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftserverless/types"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubAPI answers for a single workgroup, each GetCredentials hands out the
+// next password.
+type stubAPI struct {
+	workgroup   string
+	endpoint    *types.Endpoint
+	expiration  time.Time
+	credentials int
+}
+
+func (s *stubAPI) GetWorkgroup(ctx context.Context, params *redshiftserverless.GetWorkgroupInput, optFns ...func(*redshiftserverless.Options)) (*redshiftserverless.GetWorkgroupOutput, error) {
+	if aws.ToString(params.WorkgroupName) != s.workgroup {
+		return nil, errors.New("ResourceNotFoundException: Workgroup not found")
+	}
+
+	return &redshiftserverless.GetWorkgroupOutput{
+		Workgroup: &types.Workgroup{WorkgroupName: params.WorkgroupName, Endpoint: s.endpoint},
+	}, nil
+}
+
+func (s *stubAPI) GetCredentials(ctx context.Context, params *redshiftserverless.GetCredentialsInput, optFns ...func(*redshiftserverless.Options)) (*redshiftserverless.GetCredentialsOutput, error) {
+	if aws.ToString(params.WorkgroupName) != s.workgroup {
+		return nil, errors.New("ResourceNotFoundException: Workgroup not found")
+	}
+
+	s.credentials++
+
+	return &redshiftserverless.GetCredentialsOutput{
+		DbUser:     aws.String("IAMR:deployer"),
+		DbPassword: aws.String(time.Duration(s.credentials).String()),
+		Expiration: aws.Time(s.expiration),
+	}, nil
+}
+
+func Test_ResolveEndpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		workgroup   string
+		endpoint    *types.Endpoint
+		expected    *Endpoint
+		expectedErr string
+	}{
+		"resolved": {
+			workgroup: "analytics",
+			endpoint:  &types.Endpoint{Address: aws.String("analytics.123456789012.eu-west-1.redshift-serverless.amazonaws.com"), Port: aws.Int32(5439)},
+			expected:  &Endpoint{Host: "analytics.123456789012.eu-west-1.redshift-serverless.amazonaws.com", Port: 5439},
+		},
+		"still_creating": {
+			workgroup:   "analytics",
+			expectedErr: "has no endpoint yet",
+		},
+		"unknown_workgroup": {
+			workgroup:   "missing",
+			expectedErr: "Workgroup not found",
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			api := &stubAPI{workgroup: "analytics", endpoint: test.endpoint}
+
+			endpoint, err := ResolveEndpoint(context.Background(), api, test.workgroup)
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, endpoint)
+		})
+	}
+}
+
+func Test_CredentialSource(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	api := &stubAPI{workgroup: "analytics", expiration: now.Add(15 * time.Minute)}
+
+	source := NewCredentialSource(api, "analytics", "dev")
+	source.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	first, err := source.Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "IAMR:deployer", first.Username)
+
+	// still valid, no new call
+	now = now.Add(10 * time.Minute)
+	second, err := source.Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, api.credentials)
+
+	// within the refresh margin of expiring
+	now = now.Add(4*time.Minute + 30*time.Second)
+	api.expiration = now.Add(15 * time.Minute)
+	cfg := &pgx.ConnConfig{}
+	assert.NoError(t, source.BeforeConnect(ctx, cfg))
+	assert.Equal(t, 2, api.credentials)
+	assert.Equal(t, "IAMR:deployer", cfg.User)
+	assert.NotEqual(t, first.Password, cfg.Password)
+}
+
+func Test_CredentialSource_unknown_workgroup(t *testing.T) {
+	t.Parallel()
+
+	source := NewCredentialSource(&stubAPI{workgroup: "analytics"}, "missing", "dev")
+
+	err := source.BeforeConnect(context.Background(), &pgx.ConnConfig{})
+	assert.ErrorContains(t, err, "Workgroup not found")
+}
+
+func Test_EndpointSource(t *testing.T) {
+	t.Parallel()
+
+	address := "analytics.123456789012.eu-west-1.redshift-serverless.amazonaws.com"
+
+	tests := map[string]struct {
+		cfg                *pgx.ConnConfig
+		expectedHost       string
+		expectedPort       uint16
+		expectedServerName string
+	}{
+		"left_out": {
+			cfg: &pgx.ConnConfig{Config: pgconn.Config{
+				TLSConfig: &tls.Config{},
+				Fallbacks: []*pgconn.FallbackConfig{{}},
+			}},
+			expectedHost:       address,
+			expectedPort:       5439,
+			expectedServerName: address,
+		},
+		"ssl_server_name": {
+			cfg: &pgx.ConnConfig{Config: pgconn.Config{
+				TLSConfig: &tls.Config{ServerName: "redshift.example.com"},
+				Fallbacks: []*pgconn.FallbackConfig{{}},
+			}},
+			expectedHost:       address,
+			expectedPort:       5439,
+			expectedServerName: "redshift.example.com",
+		},
+		"port_configured": {
+			cfg: &pgx.ConnConfig{Config: pgconn.Config{
+				Port:      5440,
+				TLSConfig: &tls.Config{},
+			}},
+			expectedHost:       address,
+			expectedPort:       5440,
+			expectedServerName: address,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			api := &stubAPI{workgroup: "analytics", endpoint: &types.Endpoint{Address: aws.String(address), Port: aws.Int32(5439)}}
+			source := NewEndpointSource(api, "analytics")
+
+			assert.NoError(t, source.BeforeConnect(context.Background(), test.cfg))
+			assert.Equal(t, test.expectedHost, test.cfg.Host)
+			assert.Equal(t, test.expectedPort, test.cfg.Port)
+			assert.Equal(t, test.expectedServerName, test.cfg.TLSConfig.ServerName)
+			for _, fallback := range test.cfg.Fallbacks {
+				assert.Equal(t, test.expectedHost, fallback.Host)
+				assert.Equal(t, test.expectedPort, fallback.Port)
+			}
+
+			// looked up once, the workgroup is not asked again
+			api.endpoint = nil
+			assert.NoError(t, source.BeforeConnect(context.Background(), &pgx.ConnConfig{}))
+		})
+	}
+}
+
+func Test_EndpointSource_still_creating(t *testing.T) {
+	t.Parallel()
+
+	api := &stubAPI{workgroup: "analytics"}
+	source := NewEndpointSource(api, "analytics")
+
+	err := source.BeforeConnect(context.Background(), &pgx.ConnConfig{})
+	assert.ErrorContains(t, err, "has no endpoint yet")
+
+	// tried again once the workgroup has an endpoint
+	api.endpoint = &types.Endpoint{Address: aws.String("10.0.0.1"), Port: aws.Int32(5439)}
+	cfg := &pgx.ConnConfig{Config: pgconn.Config{TLSConfig: &tls.Config{}}}
+	assert.NoError(t, source.BeforeConnect(context.Background(), cfg))
+	assert.Equal(t, "10.0.0.1", cfg.Host)
+	// as for any IP address, no server name is sent
+	assert.Empty(t, cfg.TLSConfig.ServerName)
+}
@@ -0,0 +1,276 @@
+// Package tunnel reaches the cluster through an SSH bastion, connections are
+// forwarded by the bastion as direct-tcpip channels.
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const DefaultPort = 22
+
+type Config struct {
+	Host string
+	Port int
+	User string
+	// PEM encoded private key
+	PrivateKey           string
+	PrivateKeyPassphrase string
+	// authenticate with the keys of the agent at SSH_AUTH_SOCK
+	UseAgent bool
+	// the bastion's host key is verified against this file, ~/.ssh/known_hosts
+	// when empty and no HostKey is given
+	KnownHostsFile string
+	// the bastion's public key in authorized_keys format, instead of a
+	// known_hosts file
+	HostKey string
+}
+
+// Tunnel holds the SSH connection to the bastion, which is made again when
+// it has dropped.
+type Tunnel struct {
+	addr   string
+	config *ssh.ClientConfig
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+// New prepares the tunnel without connecting, the bastion is first reached
+// by DialFunc. Credentials and the host key are checked up front.
+func New(cfg Config) (*Tunnel, error) {
+	if cfg.Port == 0 {
+		cfg.Port = DefaultPort
+	}
+
+	auth, err := authMethods(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("New: %w", err)
+	}
+
+	hostKeyCallback, err := hostKeyCallback(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("New: %w", err)
+	}
+
+	return &Tunnel{
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		config: &ssh.ClientConfig{
+			User:            cfg.User,
+			Auth:            auth,
+			HostKeyCallback: hostKeyCallback,
+		},
+	}, nil
+}
+
+// DialFunc opens a connection to addr through the bastion, it is the
+// DialFunc of the pgx.ConnConfig.
+func (t *Tunnel) DialFunc(ctx context.Context, network string, addr string) (net.Conn, error) {
+	client, err := t.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.DialContext(ctx, network, addr)
+	if err == nil {
+		return conn, nil
+	}
+
+	// the cluster refusing the connection, or the dial being cancelled,
+	// leaves the SSH connection as good as it was, and the other connections
+	// forwarded over it must not be dropped. Only when the bastion has
+	// dropped it is it made again, and the dial tried once more.
+	if ctx.Err() != nil || alive(ctx, client) {
+		return nil, fmt.Errorf("DialFunc: Failed to reach %s through the bastion: %w", addr, err)
+	}
+
+	client, reconnectErr := t.reconnect(ctx, client)
+	if reconnectErr != nil {
+		return nil, fmt.Errorf("DialFunc: Failed to reach %s through the bastion: %w", addr, errors.Join(err, reconnectErr))
+	}
+
+	conn, err = client.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("DialFunc: Failed to reach %s through the bastion: %w", addr, err)
+	}
+
+	return conn, nil
+}
+
+// alive sends a keepalive over the SSH connection, only a dead transport
+// fails to carry it. The bastion may refuse the request, it still answered.
+func alive(ctx context.Context, client *ssh.Client) bool {
+	result := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		return err == nil
+	case <-ctx.Done():
+		// no answer in time says nothing either way, keep the connection
+		return true
+	}
+}
+
+// LookupFunc leaves the cluster's host name to the bastion, it is often
+// only resolvable from inside the VPC.
+func LookupFunc(ctx context.Context, host string) ([]string, error) {
+	return []string{host}, nil
+}
+
+func (t *Tunnel) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client == nil {
+		return nil
+	}
+
+	err := t.client.Close()
+	t.client = nil
+
+	return err
+}
+
+func (t *Tunnel) current(ctx context.Context) (*ssh.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client != nil {
+		return t.client, nil
+	}
+
+	client, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.client = client
+
+	return client, nil
+}
+
+// replaces failed unless another dial has already done so.
+func (t *Tunnel) reconnect(ctx context.Context, failed *ssh.Client) (*ssh.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client != nil && t.client != failed {
+		return t.client, nil
+	}
+
+	failed.Close()
+	t.client = nil
+
+	client, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.client = client
+
+	return client, nil
+}
+
+func (t *Tunnel) connect(ctx context.Context) (*ssh.Client, error) {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return nil, fmt.Errorf("connect: Failed to reach bastion %s: %w", t.addr, err)
+	}
+
+	// the handshake has no context of its own
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline) //nolint:errcheck
+	}
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, t.addr, t.config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("connect: Failed SSH handshake with bastion %s: %w", t.addr, err)
+	}
+
+	conn.SetDeadline(time.Time{}) //nolint:errcheck
+
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+func authMethods(cfg Config) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+
+	if cfg.PrivateKey != "" {
+		var signer ssh.Signer
+		var err error
+		if cfg.PrivateKeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(cfg.PrivateKey), []byte(cfg.PrivateKeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(cfg.PrivateKey))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("authMethods: Failed to parse private key: %w", err)
+		}
+
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	if cfg.UseAgent {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return nil, errors.New("authMethods: use_agent is set but SSH_AUTH_SOCK is not")
+		}
+
+		// the agent connection lives as long as the provider
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			return nil, fmt.Errorf("authMethods: Failed to reach the SSH agent: %w", err)
+		}
+
+		methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("authMethods: Neither a private key nor the SSH agent is configured")
+	}
+
+	return methods, nil
+}
+
+func hostKeyCallback(cfg Config) (ssh.HostKeyCallback, error) {
+	if cfg.HostKey != "" {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(cfg.HostKey))
+		if err != nil {
+			return nil, fmt.Errorf("hostKeyCallback: Failed to parse host key: %w", err)
+		}
+
+		return ssh.FixedHostKey(key), nil
+	}
+
+	file := cfg.KnownHostsFile
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("hostKeyCallback: No known_hosts file given and no home directory: %w", err)
+		}
+		file = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	callback, err := knownhosts.New(file)
+	if err != nil {
+		return nil, fmt.Errorf("hostKeyCallback: Failed to read known_hosts: %w", err)
+	}
+
+	return callback, nil
+}
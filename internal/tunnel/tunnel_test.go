@@ -0,0 +1,362 @@
+package tunnel
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// bastion is an in-process SSH server which forwards direct-tcpip channels,
+// the host cluster.internal stands for the echo server behind it.
+type bastion struct {
+	listener net.Listener
+	hostKey  ssh.Signer
+	echo     net.Listener
+
+	mu      sync.Mutex
+	conns   []*ssh.ServerConn
+	targets []string
+}
+
+func newBastion(t *testing.T, authorized ssh.PublicKey) *bastion {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate host key: %s", err)
+	}
+	hostKey, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("failed to create host key signer: %s", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	echo, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { echo.Close() })
+
+	go func() {
+		for {
+			conn, err := echo.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn) //nolint:errcheck
+			}()
+		}
+	}()
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "tunnel" && string(key.Marshal()) == string(authorized.Marshal()) {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	b := &bastion{listener: listener, hostKey: hostKey, echo: echo}
+	go b.serve(config)
+
+	return b
+}
+
+func (b *bastion) serve(config *ssh.ServerConfig) {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			sconn, chans, reqs, err := ssh.NewServerConn(conn, config)
+			if err != nil {
+				conn.Close()
+				return
+			}
+
+			b.mu.Lock()
+			b.conns = append(b.conns, sconn)
+			b.mu.Unlock()
+
+			go ssh.DiscardRequests(reqs)
+			for newChannel := range chans {
+				go b.forward(newChannel)
+			}
+		}()
+	}
+}
+
+func (b *bastion) forward(newChannel ssh.NewChannel) {
+	var target struct {
+		Host     string
+		Port     uint32
+		OrigHost string
+		OrigPort uint32
+	}
+	if newChannel.ChannelType() != "direct-tcpip" || ssh.Unmarshal(newChannel.ExtraData(), &target) != nil {
+		newChannel.Reject(ssh.UnknownChannelType, "only direct-tcpip") //nolint:errcheck
+		return
+	}
+
+	b.mu.Lock()
+	b.targets = append(b.targets, net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+	b.mu.Unlock()
+
+	if target.Host != "cluster.internal" {
+		newChannel.Reject(ssh.ConnectionFailed, "no route to host") //nolint:errcheck
+		return
+	}
+
+	upstream, err := net.Dial("tcp", b.echo.Addr().String())
+	if err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error()) //nolint:errcheck
+		return
+	}
+
+	channel, reqs, err := newChannel.Accept()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	go func() {
+		io.Copy(channel, upstream) //nolint:errcheck
+		channel.Close()
+	}()
+	io.Copy(upstream, channel) //nolint:errcheck
+	upstream.Close()
+}
+
+// drops every client, as a bastion timing out idle sessions would.
+func (b *bastion) dropAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, conn := range b.conns {
+		conn.Close()
+	}
+	b.conns = nil
+}
+
+func (b *bastion) port() int {
+	return b.listener.Addr().(*net.TCPAddr).Port
+}
+
+func newClientKey(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate client key: %s", err)
+	}
+
+	block, err := ssh.MarshalPrivateKey(key, "")
+	if err != nil {
+		t.Fatalf("failed to marshal client key: %s", err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("failed to create client key signer: %s", err)
+	}
+
+	return string(pem.EncodeToMemory(block)), signer.PublicKey()
+}
+
+func assertEchoes(t *testing.T, conn net.Conn) {
+	t.Helper()
+	defer conn.Close()
+
+	_, err := conn.Write([]byte("ping"))
+	assert.NoError(t, err)
+
+	reply := make([]byte, 4)
+	_, err = io.ReadFull(conn, reply)
+	assert.NoError(t, err)
+	assert.Equal(t, "ping", string(reply))
+}
+
+// connects tun to the bastion by dialing the cluster through it.
+func dial(ctx context.Context, tun *Tunnel) error {
+	conn, err := tun.DialFunc(ctx, "tcp", "cluster.internal:5439")
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+func Test_Tunnel(t *testing.T) {
+	t.Parallel()
+
+	privateKey, publicKey := newClientKey(t)
+	b := newBastion(t, publicKey)
+	hostKey := string(ssh.MarshalAuthorizedKey(b.hostKey.PublicKey()))
+
+	ctx := context.Background()
+
+	t.Run("forwards_unresolved_host", func(t *testing.T) {
+		tun, err := New(Config{Host: "127.0.0.1", Port: b.port(), User: "tunnel", PrivateKey: privateKey, HostKey: hostKey})
+		if err != nil {
+			t.Fatalf("failed to create: %s", err)
+		}
+		defer tun.Close()
+
+		// as pgconn does, which resolves the host with LookupFunc first
+		hosts, err := LookupFunc(ctx, "cluster.internal")
+		assert.NoError(t, err)
+
+		conn, err := tun.DialFunc(ctx, "tcp", net.JoinHostPort(hosts[0], "5439"))
+		if err != nil {
+			t.Fatalf("failed to dial: %s", err)
+		}
+		assertEchoes(t, conn)
+
+		b.mu.Lock()
+		assert.Contains(t, b.targets, "cluster.internal:5439")
+		b.mu.Unlock()
+	})
+
+	t.Run("reconnects_after_drop", func(t *testing.T) {
+		tun, err := New(Config{Host: "127.0.0.1", Port: b.port(), User: "tunnel", PrivateKey: privateKey, HostKey: hostKey})
+		if err != nil {
+			t.Fatalf("failed to create: %s", err)
+		}
+		defer tun.Close()
+
+		if err := dial(ctx, tun); err != nil {
+			t.Fatalf("failed to dial: %s", err)
+		}
+
+		b.dropAll()
+
+		conn, err := tun.DialFunc(ctx, "tcp", "cluster.internal:5439")
+		if err != nil {
+			t.Fatalf("failed to dial: %s", err)
+		}
+		assertEchoes(t, conn)
+	})
+
+	t.Run("keeps_connection_after_refused_dial", func(t *testing.T) {
+		tun, err := New(Config{Host: "127.0.0.1", Port: b.port(), User: "tunnel", PrivateKey: privateKey, HostKey: hostKey})
+		if err != nil {
+			t.Fatalf("failed to create: %s", err)
+		}
+		defer tun.Close()
+
+		// another pooled connection forwarded over the same SSH connection
+		pooled, err := tun.DialFunc(ctx, "tcp", "cluster.internal:5439")
+		if err != nil {
+			t.Fatalf("failed to dial: %s", err)
+		}
+		client := tun.client
+
+		_, err = tun.DialFunc(ctx, "tcp", "elsewhere.internal:5439")
+		assert.ErrorContains(t, err, "no route to host")
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = tun.DialFunc(cancelled, "tcp", "cluster.internal:5439")
+		assert.Error(t, err)
+
+		assert.Same(t, client, tun.client)
+		assertEchoes(t, pooled)
+	})
+
+	t.Run("connects_on_first_dial", func(t *testing.T) {
+		tun, err := New(Config{Host: "127.0.0.1", Port: b.port(), User: "tunnel", PrivateKey: privateKey, HostKey: hostKey})
+		if err != nil {
+			t.Fatalf("failed to create: %s", err)
+		}
+		defer tun.Close()
+
+		assert.Nil(t, tun.client)
+
+		conn, err := tun.DialFunc(ctx, "tcp", "cluster.internal:5439")
+		if err != nil {
+			t.Fatalf("failed to dial: %s", err)
+		}
+		assertEchoes(t, conn)
+	})
+
+	t.Run("known_hosts", func(t *testing.T) {
+		knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+		line := knownhosts.Line([]string{net.JoinHostPort("127.0.0.1", strconv.Itoa(b.port()))}, b.hostKey.PublicKey())
+		if err := os.WriteFile(knownHosts, []byte(line+"\n"), 0o600); err != nil {
+			t.Fatalf("failed to write known_hosts: %s", err)
+		}
+
+		tun, err := New(Config{Host: "127.0.0.1", Port: b.port(), User: "tunnel", PrivateKey: privateKey, KnownHostsFile: knownHosts})
+		if err != nil {
+			t.Fatalf("failed to create: %s", err)
+		}
+		defer tun.Close()
+
+		assert.NoError(t, dial(ctx, tun))
+	})
+
+	t.Run("unknown_host_key", func(t *testing.T) {
+		knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+		if err := os.WriteFile(knownHosts, nil, 0o600); err != nil {
+			t.Fatalf("failed to write known_hosts: %s", err)
+		}
+
+		tun, err := New(Config{Host: "127.0.0.1", Port: b.port(), User: "tunnel", PrivateKey: privateKey, KnownHostsFile: knownHosts})
+		if err != nil {
+			t.Fatalf("failed to create: %s", err)
+		}
+
+		assert.ErrorContains(t, dial(ctx, tun), "key is unknown")
+	})
+
+	t.Run("wrong_host_key", func(t *testing.T) {
+		_, other := newClientKey(t)
+
+		tun, err := New(Config{Host: "127.0.0.1", Port: b.port(), User: "tunnel", PrivateKey: privateKey, HostKey: string(ssh.MarshalAuthorizedKey(other))})
+		if err != nil {
+			t.Fatalf("failed to create: %s", err)
+		}
+
+		assert.ErrorContains(t, dial(ctx, tun), "host key mismatch")
+	})
+
+	t.Run("unauthorized_key", func(t *testing.T) {
+		otherKey, _ := newClientKey(t)
+
+		tun, err := New(Config{Host: "127.0.0.1", Port: b.port(), User: "tunnel", PrivateKey: otherKey, HostKey: hostKey})
+		if err != nil {
+			t.Fatalf("failed to create: %s", err)
+		}
+
+		assert.ErrorContains(t, dial(ctx, tun), "unable to authenticate")
+	})
+
+	t.Run("no_credentials", func(t *testing.T) {
+		_, err := New(Config{Host: "127.0.0.1", Port: b.port(), User: "tunnel", HostKey: hostKey})
+		assert.ErrorContains(t, err, "Neither a private key nor the SSH agent")
+	})
+}
@@ -0,0 +1,89 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/helpers/validatordiag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = argumentTypesValidator{}
+
+type argumentTypesValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (v argumentTypesValidator) Description(_ context.Context) string {
+	return "value must be empty or a comma separated list of type names, such as integer, numeric(10,2), " +
+		"made of letters, digits, underscores, spaces, periods and balanced parentheses"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v argumentTypesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v argumentTypesValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		// Only validate if value is known
+		return
+	}
+
+	if reason := v.invalid(req.ConfigValue.ValueString()); reason != "" {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx)+", "+reason,
+			req.ConfigValue.ValueString(),
+		))
+	}
+}
+
+// invalid is why arguments is not a list of type names, empty when it is.
+func (v argumentTypesValidator) invalid(arguments string) string {
+	if strings.TrimSpace(arguments) == "" {
+		return ""
+	}
+
+	depth := 0
+	typeName := ""
+	for _, r := range arguments {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return "it closes a parenthesis which is not open"
+			}
+		case r == ',' && depth == 0:
+			if strings.TrimSpace(typeName) == "" {
+				return "it has an empty type name"
+			}
+			typeName = ""
+			continue
+		case r == '_', r == ' ', r == '.', r == ',',
+			'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		default:
+			return fmt.Sprintf("it contains %q", r)
+		}
+		typeName += string(r)
+	}
+
+	switch {
+	case depth > 0:
+		return "it leaves a parenthesis open"
+	case strings.TrimSpace(typeName) == "":
+		return "it has an empty type name"
+	}
+
+	return ""
+}
+
+// ArgumentTypesValidator returns a validator which ensures a value lists the
+// argument types of a function or procedure, which the provider places
+// between the parentheses of its signature as they are.
+func ArgumentTypesValidator() validator.String {
+	return argumentTypesValidator{}
+}
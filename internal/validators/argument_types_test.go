@@ -0,0 +1,86 @@
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_ArgumentTypesValidator(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		val         types.String
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"unknown": {
+			val: types.StringUnknown(),
+		},
+		"null": {
+			val: types.StringNull(),
+		},
+		"no_arguments": {
+			val: types.StringValue(""),
+		},
+		"types": {
+			val: types.StringValue("integer, character varying"),
+		},
+		"type_modifiers": {
+			val: types.StringValue("numeric(10,2), varchar(64)"),
+		},
+		"qualified_type": {
+			val: types.StringValue("pg_catalog.int4"),
+		},
+		"semicolon": {
+			val:         types.StringValue("integer); DROP TABLE users; --"),
+			expectError: true,
+		},
+		"quote": {
+			val:         types.StringValue(`integer, "text"`),
+			expectError: true,
+		},
+		"unclosed_parenthesis": {
+			val:         types.StringValue("numeric(10,2"),
+			expectError: true,
+		},
+		"unopened_parenthesis": {
+			val:         types.StringValue("integer), (integer"),
+			expectError: true,
+		},
+		"empty_type": {
+			val:         types.StringValue("integer,,text"),
+			expectError: true,
+		},
+		"trailing_comma": {
+			val:         types.StringValue("integer,"),
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			request := validator.StringRequest{
+				Path:           path.Root("test"),
+				PathExpression: path.MatchRoot("test"),
+				ConfigValue:    test.val,
+			}
+			response := validator.StringResponse{}
+
+			ArgumentTypesValidator().ValidateString(context.TODO(), request, &response)
+
+			if !response.Diagnostics.HasError() && test.expectError {
+				t.Fatal("expected error, got no error")
+			}
+
+			if response.Diagnostics.HasError() && !test.expectError {
+				t.Fatalf("got unexpected error: %s", response.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"terraform-provider-redshift/internal/helpers"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/helpers/validatordiag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// IdentifierKind chooses the rules a name is held to.
+type IdentifierKind int
+
+const (
+	// the provider quotes the name, so it may hold any printable character
+	// but a double quote
+	IdentifierQuoted IdentifierKind = iota
+	// letters, digits, underscores and dollar signs, as a name without quotes
+	IdentifierStandard
+	// a standard identifier which does not begin with two underscores, redshift
+	// reserves those group names
+	IdentifierGroup
+)
+
+var _ validator.String = identifierValidator{}
+
+type identifierValidator struct {
+	kind IdentifierKind
+}
+
+// Description describes the validation in plain text formatting.
+func (v identifierValidator) Description(_ context.Context) string {
+	switch v.kind {
+	case IdentifierStandard:
+		return fmt.Sprintf("value must be a standard identifier of at most %d bytes, beginning with a letter or underscore and "+
+			"followed by letters, digits, underscores or dollar signs, and not a reserved word", helpers.IdentifierMaxBytes)
+	case IdentifierGroup:
+		return fmt.Sprintf("value must be a standard identifier of at most %d bytes, beginning with a letter or a single underscore and "+
+			"followed by letters, digits, underscores or dollar signs, and not a reserved word", helpers.IdentifierMaxBytes)
+	default:
+		return fmt.Sprintf("value must be an identifier of at most %d bytes of printable characters other than a double quote, "+
+			"and not a reserved word", helpers.IdentifierMaxBytes)
+	}
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v identifierValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v identifierValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		// Only validate if value is known
+		return
+	}
+
+	if reason := v.invalid(req.ConfigValue.ValueString()); reason != "" {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx)+", "+reason,
+			req.ConfigValue.ValueString(),
+		))
+	}
+}
+
+// invalid is why name breaks the rules, empty when it does not.
+func (v identifierValidator) invalid(name string) string {
+	switch {
+	case name == "":
+		return "it is empty"
+	case len(name) > helpers.IdentifierMaxBytes:
+		return fmt.Sprintf("it is %d bytes long", len(name))
+	case !utf8.ValidString(name):
+		return "it is not valid UTF-8"
+	case slices.Contains(helpers.ReservedWords, strings.ToLower(name)):
+		return "it is a reserved word"
+	}
+
+	if v.kind == IdentifierQuoted {
+		for _, r := range name {
+			if r == '"' || !unicode.IsPrint(r) {
+				return fmt.Sprintf("it contains %q", r)
+			}
+		}
+
+		return ""
+	}
+
+	if v.kind == IdentifierGroup && strings.HasPrefix(name, "__") {
+		return "it begins with two underscores"
+	}
+
+	for i, r := range name {
+		// redshift allows any multibyte UTF-8 character without quotes
+		switch {
+		case r >= utf8.RuneSelf:
+		case r == '_', 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+		case i > 0 && (r == '$' || '0' <= r && r <= '9'):
+		default:
+			return fmt.Sprintf("it contains %q", r)
+		}
+	}
+
+	return ""
+}
+
+// IdentifierValidator returns a validator which ensures a name follows the
+// rules redshift has for names of kind, see
+// https://docs.aws.amazon.com/redshift/latest/dg/r_names.html
+func IdentifierValidator(kind IdentifierKind) validator.String {
+	return identifierValidator{
+		kind: kind,
+	}
+}
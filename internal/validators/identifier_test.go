@@ -0,0 +1,118 @@
+package validators
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_IdentifierValidator(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		kind        IdentifierKind
+		val         types.String
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"unknown": {
+			val: types.StringUnknown(),
+		},
+		"null": {
+			val: types.StringNull(),
+		},
+		"quoted_spaces_and_punctuation": {
+			val: types.StringValue("etl user-1.prod"),
+		},
+		"quoted_double_quote": {
+			kind:        IdentifierQuoted,
+			val:         types.StringValue(`bob"; DROP USER admin; --`),
+			expectError: true,
+		},
+		"quoted_control_character": {
+			kind:        IdentifierQuoted,
+			val:         types.StringValue("bob\n"),
+			expectError: true,
+		},
+		"empty": {
+			val:         types.StringValue(""),
+			expectError: true,
+		},
+		"reserved_word": {
+			val:         types.StringValue("Select"),
+			expectError: true,
+		},
+		"127_bytes": {
+			val: types.StringValue(strings.Repeat("a", 127)),
+		},
+		"128_bytes": {
+			kind:        IdentifierStandard,
+			val:         types.StringValue(strings.Repeat("a", 128)),
+			expectError: true,
+		},
+		"multibyte_over_127_bytes": {
+			kind:        IdentifierQuoted,
+			val:         types.StringValue(strings.Repeat("é", 64)),
+			expectError: true,
+		},
+		"standard": {
+			kind: IdentifierStandard,
+			val:  types.StringValue("_tf_etl$2"),
+		},
+		"standard_multibyte": {
+			kind: IdentifierStandard,
+			val:  types.StringValue("données"),
+		},
+		"standard_leading_digit": {
+			kind:        IdentifierStandard,
+			val:         types.StringValue("2fa"),
+			expectError: true,
+		},
+		"standard_leading_dollar": {
+			kind:        IdentifierStandard,
+			val:         types.StringValue("$tf"),
+			expectError: true,
+		},
+		"standard_hyphen": {
+			kind:        IdentifierStandard,
+			val:         types.StringValue("tf-etl"),
+			expectError: true,
+		},
+		"group": {
+			kind: IdentifierGroup,
+			val:  types.StringValue("_tf_readers"),
+		},
+		"group_two_underscores": {
+			kind:        IdentifierGroup,
+			val:         types.StringValue("__readers"),
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			request := validator.StringRequest{
+				Path:           path.Root("test"),
+				PathExpression: path.MatchRoot("test"),
+				ConfigValue:    test.val,
+			}
+			response := validator.StringResponse{}
+
+			IdentifierValidator(test.kind).ValidateString(context.TODO(), request, &response)
+
+			if !response.Diagnostics.HasError() && test.expectError {
+				t.Fatal("expected error, got no error")
+			}
+
+			if response.Diagnostics.HasError() && !test.expectError {
+				t.Fatalf("got unexpected error: %s", response.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/helpers/validatordiag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// values of the provider's identifier_case setting
+const (
+	IdentifierCaseLower    = "lower"
+	IdentifierCasePreserve = "preserve"
+)
+
+var _ validator.String = namePolicyValidator{}
+
+type namePolicyValidator struct {
+	prefix         string
+	identifierCase string
+}
+
+// Description describes the validation in plain text formatting.
+func (v namePolicyValidator) Description(_ context.Context) string {
+	var rules []string
+	if v.prefix != "" {
+		rules = append(rules, fmt.Sprintf("start with %q", v.prefix))
+	}
+	if v.identifierCase == IdentifierCaseLower {
+		rules = append(rules, "be in lower case")
+	}
+
+	return "value must " + strings.Join(rules, " and ") + ", as the provider requires"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v namePolicyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// Validate performs the validation.
+func (v namePolicyValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		// Only validate if value is known
+		return
+	}
+
+	name := req.ConfigValue.ValueString()
+	if !strings.HasPrefix(name, v.prefix) ||
+		(v.identifierCase == IdentifierCaseLower && name != strings.ToLower(name)) {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx),
+			name,
+		))
+	}
+}
+
+// NamePolicyValidator returns a validator which ensures a name follows the
+// naming policy of the provider: it begins with prefix, and is in lower case
+// when identifierCase is lower. It is nil when there is no policy. The policy
+// is only known once the provider is configured, so resources apply it when
+// they plan rather than when their configuration is validated.
+func NamePolicyValidator(prefix string, identifierCase string) validator.String {
+	if prefix == "" && identifierCase != IdentifierCaseLower {
+		return nil
+	}
+
+	return namePolicyValidator{
+		prefix:         prefix,
+		identifierCase: identifierCase,
+	}
+}
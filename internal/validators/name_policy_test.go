@@ -0,0 +1,82 @@
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_NamePolicyValidator(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		prefix         string
+		identifierCase string
+		val            types.String
+		expectError    bool
+	}
+	tests := map[string]testCase{
+		"unknown": {
+			prefix: "tf_",
+			val:    types.StringUnknown(),
+		},
+		"prefixed": {
+			prefix: "tf_",
+			val:    types.StringValue("tf_Bob"),
+		},
+		"not_prefixed": {
+			prefix:      "tf_",
+			val:         types.StringValue("bob"),
+			expectError: true,
+		},
+		"prefix_is_case_sensitive": {
+			prefix:      "tf_",
+			val:         types.StringValue("TF_bob"),
+			expectError: true,
+		},
+		"lower": {
+			identifierCase: IdentifierCaseLower,
+			val:            types.StringValue("bob_2"),
+		},
+		"not_lower": {
+			identifierCase: IdentifierCaseLower,
+			val:            types.StringValue("Bob"),
+			expectError:    true,
+		},
+		"preserve": {
+			prefix:         "tf_",
+			identifierCase: IdentifierCasePreserve,
+			val:            types.StringValue("tf_Bob"),
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			request := validator.StringRequest{
+				Path:           path.Root("test"),
+				PathExpression: path.MatchRoot("test"),
+				ConfigValue:    test.val,
+			}
+			response := validator.StringResponse{}
+
+			NamePolicyValidator(test.prefix, test.identifierCase).ValidateString(context.TODO(), request, &response)
+
+			if !response.Diagnostics.HasError() && test.expectError {
+				t.Fatal("expected error, got no error")
+			}
+
+			if response.Diagnostics.HasError() && !test.expectError {
+				t.Fatalf("got unexpected error: %s", response.Diagnostics)
+			}
+		})
+	}
+
+	if NamePolicyValidator("", IdentifierCasePreserve) != nil {
+		t.Fatal("expected no validator without a policy")
+	}
+}